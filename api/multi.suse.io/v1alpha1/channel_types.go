@@ -1,13 +1,17 @@
 package v1alpha1
 
 import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // ChannelSpec defines the desired state of Channel
 type ChannelSpec struct {
-	// Vendor specifies the GPU vendor (nvidia, amd, intel)
-	// +kubebuilder:validation:Enum=nvidia;amd;intel
+	// Vendor specifies the accelerator vendor, one registered with the controllers
+	// (built-in: nvidia, amd, intel)
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
 	Vendor string `json:"vendor"`
 
 	// Channel specifies the release channel (stable, lts, canary)
@@ -16,26 +20,372 @@ type ChannelSpec struct {
 
 	// ClusterSelector defines which clusters this channel applies to
 	ClusterSelector metav1.LabelSelector `json:"clusterSelector"`
+
+	// Priority arbitrates clusters selected by several Channels of the same vendor: the Channel
+	// with the highest priority deploys to them, ties are won by the Channel whose name sorts first.
+	// +kubebuilder:default=0
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+
+	// DriftPolicy controls how detected drift is handled (Ignore, Report, Remediate)
+	// +kubebuilder:validation:Enum=Ignore;Report;Remediate
+	// +kubebuilder:default=Report
+	// +optional
+	DriftPolicy string `json:"driftPolicy,omitempty"`
+
+	// Paused stops the auto-operator from updating the channel's Fleet Bundle.
+	// Status keeps being reported and a staged rollout resumes where it stopped once unpaused.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// RolloutStrategy stages version changes across the selected clusters.
+	// Without a strategy every cluster is updated at once.
+	// +optional
+	RolloutStrategy *RolloutStrategy `json:"rolloutStrategy,omitempty"`
+
+	// RollbackOnFailure reverts the channel to its last known-good version when a rollout fails.
+	// Rollback is disabled when unset.
+	// +optional
+	RollbackOnFailure *RollbackPolicy `json:"rollbackOnFailure,omitempty"`
+
+	// RollbackTo pins the channel to the version of a revision from status.history.
+	// The channel follows its release channel again once unset.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RollbackTo *int64 `json:"rollbackTo,omitempty"`
+
+	// ValuesFrom lists ConfigMaps and Secrets in cattle-fleet-system holding Helm values, merged
+	// in order over the values generated from the version pins
+	// +optional
+	ValuesFrom []ValuesReference `json:"valuesFrom,omitempty"`
+
+	// Values are Helm values merged over the pin values and ValuesFrom
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +optional
+	Values *apiextensionsv1.JSON `json:"values,omitempty"`
+
+	// ValuesOverrides merge additional Helm values on groups of the channel's clusters. A cluster
+	// matched by several overrides only gets the first one.
+	// +optional
+	ValuesOverrides []ValuesOverride `json:"valuesOverrides,omitempty"`
+}
+
+// ValuesReference selects Helm values stored as YAML in a key of a ConfigMap or Secret
+type ValuesReference struct {
+	// Kind of the object holding the values
+	// +kubebuilder:validation:Enum=ConfigMap;Secret
+	Kind string `json:"kind"`
+
+	// Name of the object in cattle-fleet-system
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Key holding the values
+	// +kubebuilder:default="values.yaml"
+	// +optional
+	Key string `json:"key,omitempty"`
+
+	// Optional tolerates a missing object or key
+	// +optional
+	Optional bool `json:"optional,omitempty"`
+}
+
+// ValuesOverride merges Helm values on the selected clusters
+type ValuesOverride struct {
+	// ClusterSelector selects the clusters among the channel's clusters
+	ClusterSelector metav1.LabelSelector `json:"clusterSelector"`
+
+	// Values are merged over the channel's values on the selected clusters
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	Values apiextensionsv1.JSON `json:"values"`
+}
+
+// Kinds supported by ValuesReference.Kind
+const (
+	// ValuesKindConfigMap reads the values from a ConfigMap
+	ValuesKindConfigMap = "ConfigMap"
+	// ValuesKindSecret reads the values from a Secret
+	ValuesKindSecret = "Secret"
+)
+
+// RollbackPolicy defines when a failed rollout is rolled back
+type RollbackPolicy struct {
+	// FailureThreshold is the number of failed clusters that triggers a rollback
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	// +optional
+	FailureThreshold int32 `json:"failureThreshold,omitempty"`
+
+	// Timeout is how long the rollout may stay failed before it is rolled back
+	// +kubebuilder:default="10m"
+	// +optional
+	Timeout metav1.Duration `json:"timeout,omitempty"`
 }
 
+// RolloutStrategy defines how a version change is rolled out. The next wave only
+// starts once every cluster of the previous waves is ready on the new version.
+type RolloutStrategy struct {
+	// Waves are rolled out in order. Clusters matching no wave form a final wave.
+	// +optional
+	Waves []RolloutWave `json:"waves,omitempty"`
+
+	// MaxUnavailable is the number or percentage of clusters updated at a time when no waves are set
+	// +kubebuilder:validation:XIntOrString
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// RolloutWave is a group of clusters updated together
+type RolloutWave struct {
+	// Name identifies the wave
+	Name string `json:"name"`
+
+	// ClusterSelector selects the wave's clusters among the channel's clusters
+	ClusterSelector metav1.LabelSelector `json:"clusterSelector"`
+}
+
+// Drift policies supported by ChannelSpec.DriftPolicy
+const (
+	// DriftPolicyIgnore disables drift detection for the channel
+	DriftPolicyIgnore = "Ignore"
+	// DriftPolicyReport records drift in the channel status
+	DriftPolicyReport = "Report"
+	// DriftPolicyRemediate records drift and force-syncs the clusters with modified resources
+	DriftPolicyRemediate = "Remediate"
+)
+
 // ChannelStatus defines the observed state of Channel
 type ChannelStatus struct {
 	// ObservedVersion is the version currently deployed
 	ObservedVersion string `json:"observedVersion,omitempty"`
 
+	// SourceRevision is the revision of the version source, such as a Git commit SHA,
+	// the channel's pins were last resolved from
+	// +optional
+	SourceRevision string `json:"sourceRevision,omitempty"`
+
+	// VendorSource is the Helm chart source the channel's stack is deployed from
+	// +optional
+	VendorSource *ChannelVendorSource `json:"vendorSource,omitempty"`
+
 	// Phase represents the current phase of the rollout
 	// +kubebuilder:validation:Enum=Pending;RollingOut;Paused;Completed;Failed
 	Phase string `json:"phase,omitempty"`
 
+	// DesiredClusters is the number of clusters targeted by the channel
+	// +optional
+	DesiredClusters int32 `json:"desiredClusters"`
+
+	// ReadyClusters is the number of clusters running the channel's stack
+	// +optional
+	ReadyClusters int32 `json:"readyClusters"`
+
+	// FailedClusters is the number of clusters whose deployment failed
+	// +optional
+	FailedClusters int32 `json:"failedClusters"`
+
+	// PendingClusters is the number of clusters still rolling out
+	// +optional
+	PendingClusters int32 `json:"pendingClusters"`
+
+	// ExcludedClusters lists the selected clusters left to a higher-priority Channel of the same vendor
+	// +optional
+	ExcludedClusters []string `json:"excludedClusters,omitempty"`
+
+	// ClusterStates lists the rollout state per cluster, failed clusters first
+	// +kubebuilder:validation:MaxItems=50
+	ClusterStates []ClusterState `json:"clusterStates,omitempty"`
+
 	// Conditions represent the latest available observations of the channel's state
 	// +listType=map
 	// +listMapKey=type
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Drift lists the clusters whose deployed stack diverges from the channel intent
+	// +kubebuilder:validation:MaxItems=50
+	Drift []ClusterDrift `json:"drift,omitempty"`
+
+	// CurrentPins are the version pins rolled out to every cluster
+	CurrentPins *PinnedVersion `json:"currentPins,omitempty"`
+
+	// Rollout tracks a staged rollout in progress
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+
+	// LastKnownGood is the last version that completed on every targeted cluster
+	LastKnownGood *PinnedVersion `json:"lastKnownGood,omitempty"`
+
+	// FailingSince is when the current rollout started failing
+	FailingSince *metav1.Time `json:"failingSince,omitempty"`
+
+	// Rollback records an automatic rollback in effect until a new version is pinned
+	Rollback *RollbackStatus `json:"rollback,omitempty"`
+
+	// LastRemediation records the most recent automatic drift remediation
+	LastRemediation *DriftRemediation `json:"lastRemediation,omitempty"`
+
+	// History lists the revisions applied to the channel's Fleet Bundle, oldest first
+	// +kubebuilder:validation:MaxItems=10
+	History []ChannelRevision `json:"history,omitempty"`
+}
+
+// ChannelRevision records a version applied to the channel's Fleet Bundle
+type ChannelRevision struct {
+	// Revision numbers the applied versions, starting at 1
+	Revision int64 `json:"revision"`
+
+	// Version is the version pins applied
+	Version PinnedVersion `json:"version"`
+
+	// ValuesHash is a hash of the Helm values applied
+	ValuesHash string `json:"valuesHash"`
+
+	// SourceRevision is the revision of the version source the pins were resolved from,
+	// empty when the revision restores earlier pins
+	// +optional
+	SourceRevision string `json:"sourceRevision,omitempty"`
+
+	// Time is when the revision was applied
+	Time metav1.Time `json:"time"`
+
+	// Outcome is the rollout result of the revision
+	// +kubebuilder:validation:Enum=RollingOut;Completed;Failed;RolledBack;Superseded
+	Outcome string `json:"outcome"`
+
+	// RollbackOf is the revision restored by spec.rollbackTo, if any
+	// +optional
+	RollbackOf int64 `json:"rollbackOf,omitempty"`
+}
+
+// Revision outcomes reported by ChannelRevision.Outcome
+const (
+	// RevisionRollingOut is reported while the revision is being rolled out
+	RevisionRollingOut = "RollingOut"
+	// RevisionCompleted is reported once the revision is ready on every cluster
+	RevisionCompleted = "Completed"
+	// RevisionFailed is reported while the revision fails on some clusters
+	RevisionFailed = "Failed"
+	// RevisionRolledBack is reported when the revision was automatically rolled back
+	RevisionRolledBack = "RolledBack"
+	// RevisionSuperseded is reported when a newer revision was applied before this one settled
+	RevisionSuperseded = "Superseded"
+)
+
+// DriftRemediation records drift corrected by forcing a Fleet redeploy
+type DriftRemediation struct {
+	// Time is when the BundleDeployments were force-synced
+	Time metav1.Time `json:"time"`
+
+	// Corrected lists the drift that triggered the redeploy
+	// +kubebuilder:validation:MaxItems=50
+	Corrected []ClusterDrift `json:"corrected,omitempty"`
+
+	// Skipped lists clusters whose drift cannot be corrected by a redeploy
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// ChannelVendorSource reports the vendor source a Channel deploys from and where it is configured
+type ChannelVendorSource struct {
+	VendorSource `json:",inline"`
+
+	// Origin is where the source is configured: Default for the controller's vendor registry,
+	// or MultiComputeConfig/<name>
+	Origin string `json:"origin"`
+}
+
+// PinnedVersion is the set of version pins deployed for a vendor stack
+type PinnedVersion struct {
+	// OperatorTag is the operator image tag
+	OperatorTag string `json:"operatorTag"`
+
+	// RuntimeTag is the runtime image tag
+	RuntimeTag string `json:"runtimeTag"`
+
+	// ChartVersion is the version of the vendor chart, empty when not pinned
+	// +optional
+	ChartVersion string `json:"chartVersion,omitempty"`
+}
+
+// RolloutStatus describes the progress of a staged rollout
+type RolloutStatus struct {
+	// From is the version being replaced, empty for a first install
+	From *PinnedVersion `json:"from,omitempty"`
+
+	// To is the version being rolled out
+	To PinnedVersion `json:"to"`
+
+	// CurrentWave is the index of the wave being rolled out
+	CurrentWave int32 `json:"currentWave"`
+
+	// TotalWaves is the number of waves planned for the rollout
+	TotalWaves int32 `json:"totalWaves"`
+
+	// WaveName is the name of the wave being rolled out
+	WaveName string `json:"waveName,omitempty"`
+
+	// StartTime is when the rollout started
+	StartTime metav1.Time `json:"startTime"`
+}
+
+// RollbackStatus describes an automatic rollback
+type RollbackStatus struct {
+	// From is the failed version
+	From PinnedVersion `json:"from"`
+
+	// To is the last known-good version restored
+	To PinnedVersion `json:"to"`
+
+	// Time is when the rollback was triggered
+	Time metav1.Time `json:"time"`
+
+	// Reason explains why the rollback was triggered
+	Reason string `json:"reason,omitempty"`
+}
+
+// ClusterState describes the rollout state of a single cluster
+type ClusterState struct {
+	// Cluster is the name of the Fleet cluster
+	Cluster string `json:"cluster"`
+
+	// Ready reports whether the BundleDeployment is ready
+	Ready bool `json:"ready"`
+
+	// State is the Fleet display state of the BundleDeployment
+	State string `json:"state,omitempty"`
+
+	// Message explains a non-ready state
+	Message string `json:"message,omitempty"`
+}
+
+// ClusterDrift describes configuration drift detected on a single cluster
+type ClusterDrift struct {
+	// Cluster is the name of the Fleet cluster
+	Cluster string `json:"cluster"`
+
+	// Type classifies the drift
+	// +kubebuilder:validation:Enum=VersionMismatch;MissingDeployment;ModifiedResources
+	Type string `json:"type"`
+
+	// Message describes what differs from the channel intent
+	Message string `json:"message,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Vendor",type=string,JSONPath=`.spec.vendor`
+// +kubebuilder:printcolumn:name="Channel",type=string,JSONPath=`.spec.channel`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Desired",type=integer,JSONPath=`.status.desiredClusters`
+// +kubebuilder:printcolumn:name="Ready",type=integer,JSONPath=`.status.readyClusters`
+// +kubebuilder:printcolumn:name="Failed",type=integer,JSONPath=`.status.failedClusters`
+// +kubebuilder:printcolumn:name="Version",type=string,JSONPath=`.status.observedVersion`
+// +kubebuilder:printcolumn:name="Source",type=string,JSONPath=`.status.vendorSource.origin`,priority=1
+// +kubebuilder:printcolumn:name="Revision",type=integer,JSONPath=`.status.history[-1:].revision`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // Channel is the Schema for the channels API
 type Channel struct {
@@ -15,29 +15,91 @@ type MultiComputeConfigSpec struct {
 
 // PolicyConfig defines policy settings
 type PolicyConfig struct {
+	// Engine is the admission policy engine enforcing the policies: Kyverno or Gatekeeper, installed on
+	// the cluster, or the validating webhook served by the policy-controller
+	// +kubebuilder:validation:Enum=kyverno;gatekeeper;webhook
+	// +kubebuilder:default=kyverno
+	// +optional
+	Engine string `json:"engine,omitempty"`
+
+	// Audit only warns about the pods violating the policies instead of rejecting them
+	// +optional
+	Audit bool `json:"audit,omitempty"`
+
 	// EnforceRuntimeClass enables runtime class enforcement
 	EnforceRuntimeClass bool `json:"enforceRuntimeClass,omitempty"`
 
-	// RestrictGPUNamespaces restricts GPU workloads to specific namespaces
+	// RuntimeClasses maps a vendor to the RuntimeClass its GPU pods must use when EnforceRuntimeClass
+	// is set (default nvidia: nvidia). Pods of other vendors only need to set a RuntimeClass.
+	// +optional
+	RuntimeClasses map[string]string `json:"runtimeClasses,omitempty"`
+
+	// RestrictGPUNamespaces rejects pods requesting GPUs outside the namespaces of AllowedNamespaces
+	// and NamespaceSelector
 	RestrictGPUNamespaces bool `json:"restrictGPUNamespaces,omitempty"`
 
+	// AllowedNamespaces lists the namespaces allowed to run GPU workloads
+	// +optional
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+
+	// NamespaceSelector selects the namespaces allowed to run GPU workloads besides AllowedNamespaces.
+	// Defaults to the namespaces labeled multi.suse.io/gpu-workloads=true when no namespace is listed.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
 	// RequireCosign enables image signature verification
 	RequireCosign bool `json:"requireCosign,omitempty"`
 
 	// LimitGPUsPerPod sets maximum GPUs per pod
+	// +kubebuilder:validation:Minimum=0
 	LimitGPUsPerPod int32 `json:"limitGPUsPerPod,omitempty"`
+
+	// ClusterSelector selects the downstream Fleet clusters the policies are distributed to.
+	// Policies only apply to the management cluster when unset.
+	// +optional
+	ClusterSelector *metav1.LabelSelector `json:"clusterSelector,omitempty"`
 }
 
+// Policy engines supported by PolicyConfig.Engine
+const (
+	// PolicyEngineKyverno enforces the policies with Kyverno ClusterPolicies
+	PolicyEngineKyverno = "kyverno"
+	// PolicyEngineGatekeeper enforces the policies with Gatekeeper ConstraintTemplates and constraints
+	PolicyEngineGatekeeper = "gatekeeper"
+	// PolicyEngineWebhook enforces the policies with the validating webhook of the policy-controller
+	PolicyEngineWebhook = "webhook"
+)
+
 // VendorSource defines vendor-specific configuration
 type VendorSource struct {
-	// Repo is the Helm repository URL
+	// Repo is the Helm repository URL, or an OCI repository oci://registry/path holding the chart
+	// +kubebuilder:validation:Pattern=`^(https?|oci)://.+`
 	Repo string `json:"repo"`
 
-	// Chart is the Helm chart name
+	// Chart is the Helm chart name. In an OCI repository it may carry the chart version as tag,
+	// e.g. gpu-operator:v24.9.0
 	Chart string `json:"chart"`
 
 	// Namespace is the target namespace for deployment
 	Namespace string `json:"namespace"`
+
+	// HelmSecretName is a Secret in cattle-fleet-system holding the credentials of a private
+	// repository: username and password, or a token as password
+	// +optional
+	HelmSecretName string `json:"helmSecretName,omitempty"`
+
+	// CABundle is a PEM bundle of the CAs verifying the repository certificate
+	// +optional
+	CABundle string `json:"caBundle,omitempty"`
+
+	// InsecureSkipTLSVerify disables verification of the repository certificate
+	// +optional
+	InsecureSkipTLSVerify bool `json:"insecureSkipTLSVerify,omitempty"`
+
+	// ValuesTemplate is a Go template rendering the chart's Helm values as YAML from the pins
+	// (.OperatorTag, .RuntimeTag, .ChartVersion). Defaults to the template of the vendor.
+	// +optional
+	ValuesTemplate string `json:"valuesTemplate,omitempty"`
 }
 
 // MultiComputeConfigStatus defines the observed state of MultiComputeConfig
@@ -46,6 +108,32 @@ type MultiComputeConfigStatus struct {
 	// +listType=map
 	// +listMapKey=type
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Distribution reports the policies distributed to downstream clusters
+	// +optional
+	Distribution *PolicyDistributionStatus `json:"distribution,omitempty"`
+}
+
+// PolicyDistributionStatus reports the deployment of the policies to downstream clusters through Fleet
+type PolicyDistributionStatus struct {
+	// Bundle is the Fleet Bundle carrying the policies
+	Bundle string `json:"bundle"`
+
+	// DesiredClusters is the number of clusters targeted by the policies
+	DesiredClusters int32 `json:"desiredClusters"`
+
+	// ReadyClusters is the number of clusters enforcing the policies
+	ReadyClusters int32 `json:"readyClusters"`
+
+	// FailedClusters is the number of clusters where the policies failed to deploy
+	FailedClusters int32 `json:"failedClusters"`
+
+	// PendingClusters is the number of clusters still deploying the policies
+	PendingClusters int32 `json:"pendingClusters"`
+
+	// Clusters is the per-cluster state, failed clusters first, bounded to 50 entries
+	// +optional
+	Clusters []ClusterState `json:"clusters,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -4,10 +4,13 @@ import (
 	"flag"
 	"os"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
@@ -34,8 +37,17 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var versionFlags versions.ResolverFlags
+	var vendorsConfig string
+	var configName string
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	versionFlags.BindFlags(flag.CommandLine)
+	flag.StringVar(&vendorsConfig, "vendors-config", "",
+		"File mapping additional vendor names to their Helm chart source (repo, chart, namespace). "+
+			"Entries named after a built-in vendor replace it.")
+	flag.StringVar(&configName, "multi-compute-config", controller.DefaultConfigName,
+		"MultiComputeConfig whose vendorSources override the vendor definitions. Empty disables the override.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
@@ -47,23 +59,51 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	if err := versionFlags.Validate(); err != nil {
+		setupLog.Error(err, "invalid version resolver flags")
+		os.Exit(1)
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
 		Metrics:                metricsserver.Options{BindAddress: metricsAddr},
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "compute-auto-operator-controller.multi.suse.io",
+		// Only the version ConfigMaps and Helm repository Secrets are needed, keep the informers
+		// to their namespace
+		Cache: cache.Options{ByObject: map[client.Object]cache.ByObject{
+			&corev1.ConfigMap{}: {Namespaces: map[string]cache.Config{versions.DefaultConfigMapNamespace: {}}},
+			&corev1.Secret{}:    {Namespaces: map[string]cache.Config{versions.DefaultConfigMapNamespace: {}}},
+		}},
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
+	versionResolver, err := versionFlags.NewResolver(mgr.GetClient())
+	if err != nil {
+		setupLog.Error(err, "unable to set up the version resolver")
+		os.Exit(1)
+	}
+
+	vendorRegistry := vendors.DefaultRegistry()
+	if vendorsConfig != "" {
+		vendorRegistry, err = vendors.LoadRegistryFile(vendorsConfig)
+		if err != nil {
+			setupLog.Error(err, "unable to load vendor definitions")
+			os.Exit(1)
+		}
+	}
+
 	if err = (&controller.ChannelReconciler{
 		Client:          mgr.GetClient(),
 		Scheme:          mgr.GetScheme(),
-		VersionResolver: versions.NewFileResolver("./fleet/overlays"),
-		VendorSources:   vendors.DefaultSources(),
+		Recorder:        mgr.GetEventRecorderFor("compute-auto-operator-controller"),
+		VersionResolver: versionResolver,
+		VendorSources:   vendorRegistry,
+		ConfigName:      configName,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Channel")
 		os.Exit(1)
@@ -2,22 +2,32 @@ package controller
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"strings"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	multisuseiov1alpha1 "github.com/suse/rancher-multi-compute/api/multi.suse.io/v1alpha1"
 	"github.com/suse/rancher-multi-compute/internal/fleetutil"
+	"github.com/suse/rancher-multi-compute/internal/helmvalues"
 	"github.com/suse/rancher-multi-compute/internal/vendors"
 	"github.com/suse/rancher-multi-compute/internal/versions"
 )
@@ -34,6 +44,11 @@ var (
 		Version: "v1alpha1",
 		Kind:    "BundleDeployment",
 	}
+	clusterGVK = schema.GroupVersionKind{
+		Group:   "fleet.cattle.io",
+		Version: "v1alpha1",
+		Kind:    "Cluster",
+	}
 )
 
 const (
@@ -45,14 +60,23 @@ const (
 	channelLabelKey      = "multi.suse.io/channel"
 	partOfLabelKey       = "app.kubernetes.io/part-of"
 	partOfLabelValue     = "rancher-multi-compute"
+	// maxClusterStates bounds the per-cluster entries kept in status
+	maxClusterStates = 50
+	// maxBundleNameLength keeps Bundle names usable as the fleet.cattle.io/bundle-name label value
+	maxBundleNameLength = 63
+	// rolloutRequeueInterval is how often a staged rollout checks its health gate
+	rolloutRequeueInterval = 30 * time.Second
 )
 
 // ChannelReconciler reconciles a Channel object
 type ChannelReconciler struct {
 	client.Client
 	Scheme          *runtime.Scheme
+	Recorder        record.EventRecorder
 	VersionResolver versions.Resolver
-	VendorSources   map[vendors.Vendor]vendors.Source
+	VendorSources   vendors.Registry
+	// ConfigName is the MultiComputeConfig whose vendor sources override VendorSources, none when empty
+	ConfigName string
 }
 
 //+kubebuilder:rbac:groups=multi.suse.io,resources=channels,verbs=get;list;watch;create;update;patch;delete
@@ -60,8 +84,11 @@ type ChannelReconciler struct {
 //+kubebuilder:rbac:groups=multi.suse.io,resources=channels/finalizers,verbs=update
 //+kubebuilder:rbac:groups=fleet.cattle.io,resources=bundles,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=fleet.cattle.io,resources=bundledeployments,verbs=get;list;watch
+//+kubebuilder:rbac:groups=fleet.cattle.io,resources=clusters,verbs=get;list;watch
 //+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 //+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;delete
+//+kubebuilder:rbac:groups=multi.suse.io,resources=multicomputeconfigs,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop
 func (r *ChannelReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -89,67 +116,167 @@ func (r *ChannelReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		}
 	}
 
+	// Keep reporting status but leave the Bundle untouched while paused
+	if channel.Spec.Paused {
+		return r.reconcilePaused(ctx, channel)
+	}
+
 	// Resolve versions
-	vendorPins, err := r.VersionResolver.Resolve(ctx, channel.Spec.Channel)
+	vendorPins, sourceRevision, err := r.resolveVersions(ctx, channel.Spec.Channel)
 	if err != nil {
 		logger.Error(err, "Failed to resolve versions for channel", "channel", channel.Spec.Channel)
 		return r.updateChannelStatus(ctx, channel, "Failed", "VersionResolutionError", err.Error())
 	}
 
-	var currentVendorPins versions.Pins
-	var vendorName vendors.Vendor
-	switch strings.ToLower(channel.Spec.Vendor) {
-	case string(vendors.VendorNVIDIA):
-		currentVendorPins = vendorPins.NVIDIA
-		vendorName = vendors.VendorNVIDIA
-	case string(vendors.VendorAMD):
-		currentVendorPins = vendorPins.AMD
-		vendorName = vendors.VendorAMD
-	case string(vendors.VendorIntel):
-		currentVendorPins = vendorPins.Intel
-		vendorName = vendors.VendorIntel
-	default:
-		err := fmt.Errorf("unsupported vendor: %s", channel.Spec.Vendor)
+	// Look the vendor up in the MultiComputeConfig and the registry, new vendors only need a
+	// definition and pins
+	vendorName, channelSource, err := r.resolveVendorSource(ctx, channel)
+	if err != nil {
+		logger.Error(err, "Failed to resolve vendor source")
+		return r.updateChannelStatus(ctx, channel, "Failed", "VendorSourceError", err.Error())
+	}
+	if channelSource == nil {
+		err := fmt.Errorf("unsupported vendor: %s (registered vendors: %s)",
+			channel.Spec.Vendor, strings.Join(r.VendorSources.Names(), ", "))
 		logger.Error(err, "Invalid vendor specified in Channel")
 		return r.updateChannelStatus(ctx, channel, "Failed", "InvalidVendor", err.Error())
 	}
+	vendorSource := vendors.Source{
+		Repo:                  channelSource.Repo,
+		Chart:                 channelSource.Chart,
+		Namespace:             channelSource.Namespace,
+		HelmSecretName:        channelSource.HelmSecretName,
+		CABundle:              channelSource.CABundle,
+		InsecureSkipTLSVerify: channelSource.InsecureSkipTLSVerify,
+		ValuesTemplate:        channelSource.ValuesTemplate,
+	}
+	if err := vendorSource.Validate(); err != nil {
+		err = fmt.Errorf("invalid source for vendor %s (%s): %w", vendorName, channelSource.Origin, err)
+		logger.Error(err, "Invalid vendor source configuration")
+		return r.updateChannelStatus(ctx, channel, "Failed", "InvalidVendorSource", err.Error())
+	}
+	currentVendorPins, exists := vendorPins.ForVendor(string(vendorName))
+	if !exists {
+		err := fmt.Errorf("no version pins for vendor %s in channel %s", vendorName, channel.Spec.Channel)
+		logger.Error(err, "Missing vendor version pins")
+		return r.updateChannelStatus(ctx, channel, "Failed", "MissingVendorPins", err.Error())
+	}
+	channelValues, err := helmvalues.Load(ctx, r, fleetSystemNamespace, &channel.Spec)
+	if err != nil {
+		logger.Error(err, "Failed to load Helm values")
+		return r.updateChannelStatus(ctx, channel, "Failed", "ValuesError", err.Error())
+	}
 
-	desiredVersion := fmt.Sprintf("%s/%s", currentVendorPins.OperatorTag, currentVendorPins.RuntimeTag)
+	// Stay on the last known-good version while an automatic rollback is in effect
+	original := channel.Status.DeepCopy()
+	channel.Status.SourceRevision = sourceRevision
+	channel.Status.VendorSource = channelSource
+	resolvedPins := currentVendorPins
+	currentVendorPins = r.effectivePins(ctx, channel, currentVendorPins)
+	if channel.Spec.RollbackTo != nil {
+		// A manual rollback pins the version of a previous revision
+		currentVendorPins, err = revisionPins(channel)
+		if err != nil {
+			logger.Error(err, "Invalid rollback revision")
+			return r.updateChannelStatus(ctx, channel, "Failed", "RevisionNotFound", err.Error())
+		}
+	}
+	desiredVersion := formatPins(toPinnedVersion(currentVendorPins))
 
-	// Get vendor source
-	vendorSource, exists := r.VendorSources[vendorName]
-	if !exists {
-		err := fmt.Errorf("no source configuration found for vendor: %s", vendorName)
-		logger.Error(err, "Missing vendor source configuration")
-		return r.updateChannelStatus(ctx, channel, "Failed", "MissingVendorSource", err.Error())
-	}
-
-	// Create Fleet targets
-	targets := fleetutil.ConvertLabelSelectorToTargets(channel.Spec.ClusterSelector, &fleetutil.BundleDeploymentOptions{
-		DefaultNamespace: vendorSource.Namespace,
-		Helm: &fleetutil.HelmOptions{
-			ReleaseName: fmt.Sprintf("%s-%s", strings.ToLower(channel.Spec.Vendor), channel.Spec.Channel),
-			Repo:        vendorSource.Repo,
-			Chart:       vendorSource.Chart,
-			Values:      r.buildHelmValues(currentVendorPins),
-		},
-	})
+	// Leave clusters shared with higher-priority Channels of the vendor to them
+	excluded, err := r.resolveOverlaps(ctx, channel)
+	if err != nil {
+		// Keep the previous exclusions until overlaps can be resolved again
+		logger.Error(err, "Failed to resolve overlapping Channels")
+	} else {
+		channel.Status.ExcludedClusters = excluded
+	}
+
+	// Create Fleet targets, staged across waves when a rollout strategy is set
+	targets, err := r.planTargets(ctx, channel, vendorSource, currentVendorPins, channelValues)
+	if err != nil {
+		logger.Error(err, "Failed to plan rollout")
+		return r.updateChannelStatus(ctx, channel, "Failed", "RolloutPlanningError", err.Error())
+	}
+	targets = fleetutil.ApplyValuesOverrides(targets, channelValues.Overrides)
+	targets = append(fleetutil.ExcludeClusters(channel.Status.ExcludedClusters), targets...)
+
+	// Provide the credentials of a private chart repository to the Fleet agents
+	helmApp, err := r.reconcileHelmAuth(ctx, channel, vendorSource)
+	if err != nil {
+		logger.Error(err, "Failed to configure Helm repository authentication")
+		return r.updateChannelStatus(ctx, channel, "Failed", "HelmAuthError", err.Error())
+	}
 
 	// Create or update Fleet Bundle
-	err = r.upsertBundle(ctx, channel, strings.ToLower(channel.Spec.Vendor), targets)
+	err = r.upsertBundle(ctx, channel, string(vendorName), targets, helmApp)
 	if err != nil {
 		logger.Error(err, "Failed to create or update Bundle")
 		return r.updateChannelStatus(ctx, channel, "Failed", "BundleCreationError", fmt.Sprintf("Failed to create/update Bundle: %v", err))
 	}
+	if currentVendorPins != resolvedPins {
+		// Rolled back pins were not produced by the current source revision
+		sourceRevision = ""
+	}
+	if err := r.recordRevision(channel, vendorSource, currentVendorPins, channelValues, sourceRevision); err != nil {
+		logger.Error(err, "Failed to record Channel revision")
+		return r.updateChannelStatus(ctx, channel, "Failed", "RevisionHistoryError", err.Error())
+	}
 
 	// Compute Channel status based on BundleDeployments
 	newPhase := r.computeChannelPhase(ctx, channel)
+	if channel.Status.Rollout != nil && newPhase == "Completed" {
+		// Clusters of later waves are still ready on the previous version
+		newPhase = "RollingOut"
+	}
+	channel.Status.ObservedVersion = desiredVersion
+	release, err := r.observeRelease(ctx, channel, vendorSource, currentVendorPins, channelValues)
+	if err != nil {
+		// Neither promote nor roll back a release that cannot be checked
+		logger.Error(err, "Failed to check the applied release")
+	}
+	setRevisionOutcome(channel, newPhase, release)
+	if r.trackRollout(ctx, channel, newPhase, currentVendorPins, release) {
+		// The status update triggers a reconcile deploying the last known-good version
+		return r.updateChannelStatus(ctx, channel, "RollingOut", "RolledBack", channel.Status.Rollback.Reason)
+	}
 
 	// Update Channel status
-	if channel.Status.Phase != newPhase || channel.Status.ObservedVersion != desiredVersion {
+	if !equality.Semantic.DeepEqual(original, &channel.Status) || original.Phase != newPhase {
 		return r.updateChannelStatus(ctx, channel, newPhase, "Reconciled", fmt.Sprintf("Channel phase changed to %s, observed version %s", newPhase, desiredVersion))
 	}
 
+	if channel.Status.Rollout != nil || (channel.Status.FailingSince != nil && channel.Spec.RollbackOnFailure != nil) {
+		return ctrl.Result{RequeueAfter: rolloutRequeueInterval}, nil
+	}
+	return ctrl.Result{RequeueAfter: 5 * time.Minute}, nil
+}
+
+// resolveVersions resolves the pins of a release channel, along with the source revision they
+// were read from when the resolver reports one
+func (r *ChannelReconciler) resolveVersions(ctx context.Context, release string) (versions.VendorPins, string, error) {
+	if resolver, ok := r.VersionResolver.(versions.RevisionResolver); ok {
+		return resolver.ResolveRevision(ctx, release)
+	}
+	pins, err := r.VersionResolver.Resolve(ctx, release)
+	return pins, "", err
+}
+
+// reconcilePaused refreshes the rollout status of a paused Channel without mutating its Fleet Bundle
+func (r *ChannelReconciler) reconcilePaused(ctx context.Context, channel *multisuseiov1alpha1.Channel) (ctrl.Result, error) {
+	original := channel.Status.DeepCopy()
+	// Exclusions are only applied to the Bundle once unpaused
+	if _, err := r.resolveOverlaps(ctx, channel); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to resolve overlapping Channels")
+	}
+	underlying := r.computeChannelPhase(ctx, channel)
+	message := fmt.Sprintf("Channel paused while %s, %d/%d clusters ready", underlying, channel.Status.ReadyClusters, channel.Status.DesiredClusters)
+
+	condition := getChannelCondition(channel.Status.Conditions, "Ready")
+	if !equality.Semantic.DeepEqual(original, &channel.Status) || original.Phase != "Paused" ||
+		condition == nil || condition.Message != message {
+		return r.updateChannelStatus(ctx, channel, "Paused", "Paused", message)
+	}
 	return ctrl.Result{RequeueAfter: 5 * time.Minute}, nil
 }
 
@@ -202,7 +329,7 @@ func (r *ChannelReconciler) updateChannelStatus(ctx context.Context, channel *mu
 	switch phase {
 	case "Failed":
 		newCondition.Status = metav1.ConditionFalse
-	case "Progressing", "Pending", "RollingOut":
+	case "Progressing", "Pending", "RollingOut", "Paused":
 		newCondition.Status = metav1.ConditionUnknown
 	}
 
@@ -223,26 +350,45 @@ func (r *ChannelReconciler) updateChannelStatus(ctx context.Context, channel *mu
 	return ctrl.Result{}, nil
 }
 
-func (r *ChannelReconciler) buildHelmValues(pins versions.Pins) map[string]interface{} {
-	return map[string]interface{}{
-		"image": map[string]string{
-			"operatorTag": pins.OperatorTag,
-			"runtimeTag":  pins.RuntimeTag,
-		},
+// buildHelmValues renders the Helm values of the vendor chart for the pins, merged with the channel values
+func (r *ChannelReconciler) buildHelmValues(source vendors.Source, pins versions.Pins, values helmvalues.Values) (map[string]interface{}, error) {
+	pinValues, err := source.HelmValues(pins)
+	if err != nil {
+		return nil, err
 	}
+	return values.Apply(pinValues), nil
 }
 
+// computeChannelPhase records the per-cluster rollout breakdown in the Channel status and returns the derived phase
 func (r *ChannelReconciler) computeChannelPhase(ctx context.Context, channel *multisuseiov1alpha1.Channel) string {
-	phase, err := r.summarizePhase(ctx, channel, strings.ToLower(channel.Spec.Vendor))
+	rollout, err := r.summarizeRollout(ctx, channel, strings.ToLower(channel.Spec.Vendor))
 	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to summarize rollout", "channel", channel.Name)
 		return "Failed"
 	}
-	return phase
+
+	channel.Status.DesiredClusters = int32(rollout.Desired)
+	channel.Status.ReadyClusters = int32(rollout.Ready)
+	channel.Status.FailedClusters = int32(rollout.Failed)
+	channel.Status.PendingClusters = int32(rollout.Pending)
+	channel.Status.ClusterStates = nil
+	for i, c := range rollout.Clusters {
+		if i == maxClusterStates {
+			break
+		}
+		channel.Status.ClusterStates = append(channel.Status.ClusterStates, multisuseiov1alpha1.ClusterState{
+			Cluster: c.Cluster,
+			Ready:   c.Ready,
+			State:   c.State,
+			Message: c.Message,
+		})
+	}
+	return rollout.Phase()
 }
 
 // upsertBundle creates or updates a Fleet Bundle using unstructured objects
-func (r *ChannelReconciler) upsertBundle(ctx context.Context, ch *multisuseiov1alpha1.Channel, vendor string, targets []fleetutil.Target) error {
-	name := fmt.Sprintf("rmc-%s-stack", vendor)
+func (r *ChannelReconciler) upsertBundle(ctx context.Context, ch *multisuseiov1alpha1.Channel, vendor string, targets []fleetutil.Target, helmApp *fleetutil.HelmAppOptions) error {
+	name := bundleName(ch)
 
 	b := &unstructured.Unstructured{}
 	b.SetGroupVersionKind(bundleGVK)
@@ -262,9 +408,16 @@ func (r *ChannelReconciler) upsertBundle(ctx context.Context, ch *multisuseiov1a
 		UID:        ch.UID,
 	}})
 
-	// Spec: .spec.helm, .spec.namespace, .spec.targets
+	// Spec: .spec.targets inlining the Helm options, .spec.helmAppOptions
+	fleetTargets, err := fleetutil.TargetsToUnstructured(targets)
+	if err != nil {
+		return err
+	}
 	spec := map[string]any{
-		"targets": targets,
+		"targets": fleetTargets,
+	}
+	if helmApp != nil {
+		spec["helmAppOptions"] = helmApp.ToUnstructured()
 	}
 	if err := unstructured.SetNestedField(b.Object, spec, "spec"); err != nil {
 		return err
@@ -276,16 +429,65 @@ func (r *ChannelReconciler) upsertBundle(ctx context.Context, ch *multisuseiov1a
 	key := client.ObjectKey{Namespace: b.GetNamespace(), Name: b.GetName()}
 	if err := r.Get(ctx, key, current); err != nil {
 		// Not found → create
-		return r.Create(ctx, b)
+		if err := r.Create(ctx, b); err != nil {
+			return err
+		}
+		return r.deleteStaleBundles(ctx, ch, name)
+	}
+	// Patch spec/labels if changed, keeping the targets force-synced by the drift detector
+	currentTargets, err := fleetutil.BundleTargets(current)
+	if err != nil {
+		return err
+	}
+	fleetTargets, err = fleetutil.TargetsToUnstructured(fleetutil.KeepForceSyncGenerations(targets, currentTargets))
+	if err != nil {
+		return err
 	}
-	// Patch spec/labels if changed
-	current.Object["spec"] = b.Object["spec"]
+	spec["targets"] = fleetTargets
+	current.Object["spec"] = spec
 	current.SetLabels(b.GetLabels())
-	return r.Update(ctx, current)
+	if err := r.Update(ctx, current); err != nil {
+		return err
+	}
+	return r.deleteStaleBundles(ctx, ch, name)
+}
+
+// deleteStaleBundles deletes the Bundles owned by the Channel other than the current one,
+// such as the per-vendor Bundles created by earlier releases.
+func (r *ChannelReconciler) deleteStaleBundles(ctx context.Context, ch *multisuseiov1alpha1.Channel, current string) error {
+	bundleList := &unstructured.UnstructuredList{}
+	bundleList.SetGroupVersionKind(bundleGVK)
+	listOpts := []client.ListOption{
+		client.InNamespace(fleetSystemNamespace),
+		client.MatchingLabels{ownerLabelKey: ch.Name},
+	}
+	if err := r.List(ctx, bundleList, listOpts...); err != nil {
+		return fmt.Errorf("failed to list owned Bundles: %w", err)
+	}
+
+	for _, bundle := range bundleList.Items {
+		if bundle.GetName() == current {
+			continue
+		}
+		log.FromContext(ctx).Info("Deleting stale Bundle", "bundle", bundle.GetName())
+		if err := r.Delete(ctx, &bundle); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete Bundle %s: %w", bundle.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// summarizeRollout aggregates the Channel's BundleDeployments into per-cluster rollout state
+func (r *ChannelReconciler) summarizeRollout(ctx context.Context, ch *multisuseiov1alpha1.Channel, vendor string) (fleetutil.RolloutSummary, error) {
+	states, err := r.bundleDeploymentStates(ctx, ch, vendor)
+	if err != nil {
+		return fleetutil.RolloutSummary{}, err
+	}
+	return fleetutil.SummarizeRollout(states, r.desiredClusters(ctx, ch)), nil
 }
 
-// summarizePhase determines the overall phase from BundleDeployments
-func (r *ChannelReconciler) summarizePhase(ctx context.Context, ch *multisuseiov1alpha1.Channel, vendor string) (string, error) {
+// bundleDeploymentStates lists the BundleDeployments Fleet created for the Channel's Bundle
+func (r *ChannelReconciler) bundleDeploymentStates(ctx context.Context, ch *multisuseiov1alpha1.Channel, vendor string) ([]fleetutil.BundleDeploymentState, error) {
 	bds := &unstructured.UnstructuredList{}
 	bds.SetGroupVersionKind(bdGVK)
 	// List by labels
@@ -294,31 +496,45 @@ func (r *ChannelReconciler) summarizePhase(ctx context.Context, ch *multisuseiov
 		ownerLabelKey:  ch.Name,
 	}
 	if err := r.List(ctx, bds, ls); err != nil {
-		return "Pending", err
+		return nil, err
 	}
-	if len(bds.Items) == 0 {
-		return "RollingOut", nil
-	}
-	anyFailed, anyReady := false, false
+
+	name := bundleName(ch)
+	states := make([]fleetutil.BundleDeploymentState, 0, len(bds.Items))
 	for i := range bds.Items {
-		// Fleet BD readiness usually shows under status fields; treat lack of ready as not ready.
-		ready, _, _ := unstructured.NestedBool(bds.Items[i].Object, "status", "ready")
-		state, _, _ := unstructured.NestedString(bds.Items[i].Object, "status", "display", "state")
-		if ready {
-			anyReady = true
-		}
-		if state == "ErrApplied" || state == "Modified" || state == "NotReady" {
-			anyFailed = true
+		state := fleetutil.ParseBundleDeployment(&bds.Items[i])
+		if state.Bundle != "" && state.Bundle != name {
+			// Left over from a stale Bundle being deleted
+			continue
 		}
+		states = append(states, state)
 	}
-	switch {
-	case anyFailed:
-		return "Failed", nil
-	case anyReady:
-		return "Completed", nil
-	default:
-		return "RollingOut", nil
+	return states, nil
+}
+
+// desiredClusters returns the number of clusters Fleet targets with the Bundle, or 0 if unknown
+func (r *ChannelReconciler) desiredClusters(ctx context.Context, ch *multisuseiov1alpha1.Channel) int {
+	b := &unstructured.Unstructured{}
+	b.SetGroupVersionKind(bundleGVK)
+	key := client.ObjectKey{Namespace: fleetSystemNamespace, Name: bundleName(ch)}
+	if err := r.Get(ctx, key, b); err != nil {
+		return 0
 	}
+	desired, _, _ := unstructured.NestedInt64(b.Object, "status", "summary", "desiredReady")
+	return int(desired)
+}
+
+// bundleName returns the name of the Fleet Bundle carrying the Channel's vendor stack.
+// Channel names are unique, so several Channels of a vendor never share a Bundle.
+// Long names are truncated and suffixed with a hash to stay a valid label value.
+func bundleName(ch *multisuseiov1alpha1.Channel) string {
+	name := bundleNamePrefix + ch.Name
+	if len(name) <= maxBundleNameLength {
+		return name
+	}
+	sum := sha256.Sum256([]byte(ch.Name))
+	suffix := hex.EncodeToString(sum[:])[:8]
+	return strings.TrimRight(name[:maxBundleNameLength-len(suffix)-1], "-.") + "-" + suffix
 }
 
 func getChannelCondition(conditions []metav1.Condition, condType string) *metav1.Condition {
@@ -335,5 +551,74 @@ func (r *ChannelReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		Named("channel-auto-operator").
 		For(&multisuseiov1alpha1.Channel{}).
+		Watches(&multisuseiov1alpha1.Channel{},
+			handler.EnqueueRequestsFromMapFunc(r.channelsOfVendor),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&corev1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(r.channelsForVersionConfig),
+			builder.WithPredicates(predicate.NewPredicateFuncs(isVersionConfig))).
+		Watches(&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.channelsForHelmCredentials),
+			builder.WithPredicates(predicate.NewPredicateFuncs(isHelmCredentials))).
+		Watches(&corev1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(r.channelsForValues(multisuseiov1alpha1.ValuesKindConfigMap)),
+			builder.WithPredicates(predicate.NewPredicateFuncs(isValuesSource))).
+		Watches(&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.channelsForValues(multisuseiov1alpha1.ValuesKindSecret)),
+			builder.WithPredicates(predicate.NewPredicateFuncs(isValuesSource))).
+		Watches(&multisuseiov1alpha1.MultiComputeConfig{},
+			handler.EnqueueRequestsFromMapFunc(r.allChannels),
+			builder.WithPredicates(predicate.NewPredicateFuncs(r.isVendorSourcesConfig), predicate.GenerationChangedPredicate{})).
 		Complete(r)
 }
+
+// isVersionConfig reports whether obj is a ConfigMap carrying the version pins of a release channel
+func isVersionConfig(obj client.Object) bool {
+	_, ok := obj.GetLabels()[versions.ChannelLabel]
+	return ok && obj.GetNamespace() == fleetSystemNamespace
+}
+
+// channelsForVersionConfig enqueues the Channels following the release channel of a changed version ConfigMap
+func (r *ChannelReconciler) channelsForVersionConfig(ctx context.Context, obj client.Object) []reconcile.Request {
+	release := obj.GetLabels()[versions.ChannelLabel]
+	channels := &multisuseiov1alpha1.ChannelList{}
+	if err := r.List(ctx, channels); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list Channels for version ConfigMap", "configMap", obj.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, channel := range channels.Items {
+		if channel.Spec.Channel == release {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&channel)})
+		}
+	}
+	return requests
+}
+
+// isValuesSource reports whether obj is in the Fleet namespace, where valuesFrom references are resolved
+func isValuesSource(obj client.Object) bool {
+	return obj.GetNamespace() == fleetSystemNamespace
+}
+
+// channelsForValues enqueues the Channels reading Helm values from a changed object of the given kind
+func (r *ChannelReconciler) channelsForValues(kind string) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		channels := &multisuseiov1alpha1.ChannelList{}
+		if err := r.List(ctx, channels); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to list Channels for values", "kind", kind, "name", obj.GetName())
+			return nil
+		}
+
+		var requests []reconcile.Request
+		for _, channel := range channels.Items {
+			for _, ref := range channel.Spec.ValuesFrom {
+				if ref.Kind == kind && ref.Name == obj.GetName() {
+					requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&channel)})
+					break
+				}
+			}
+		}
+		return requests
+	}
+}
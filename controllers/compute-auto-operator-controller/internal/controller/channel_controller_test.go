@@ -7,6 +7,7 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -47,7 +48,7 @@ func (f *fakeVersionResolver) Resolve(ctx context.Context, channel string) (vers
 	if pins, ok := f.pins[channel]; ok {
 		return pins, nil
 	}
-	return versions.VendorPins{}, nil
+	return nil, nil
 }
 
 var _ = Describe("Channel Controller (EnvTest)", func() {
@@ -68,9 +69,9 @@ var _ = Describe("Channel Controller (EnvTest)", func() {
 		mockResolver = &fakeVersionResolver{
 			pins: map[string]versions.VendorPins{
 				"stable": {
-					NVIDIA: versions.Pins{OperatorTag: "v24.9.0", RuntimeTag: "12.4.1"},
-					AMD:    versions.Pins{OperatorTag: "v24.9.0", RuntimeTag: "5.7.1"},
-					Intel:  versions.Pins{OperatorTag: "v24.9.0", RuntimeTag: "24.16.0"},
+					"nvidia": {OperatorTag: "v24.9.0", RuntimeTag: "12.4.1"},
+					"amd":    {OperatorTag: "v24.9.0", RuntimeTag: "5.7.1"},
+					"intel":  {OperatorTag: "v24.9.0", RuntimeTag: "24.16.0"},
 				},
 			},
 		}
@@ -80,13 +81,14 @@ var _ = Describe("Channel Controller (EnvTest)", func() {
 			Client:          testEnv.GetClient(),
 			Scheme:          testEnv.GetScheme(),
 			VersionResolver: mockResolver,
-			VendorSources: map[vendors.Vendor]vendors.Source{
+			VendorSources: vendors.Registry{
 				vendors.VendorNVIDIA: {
 					Repo:      "https://nvidia.github.io/helm-charts",
 					Chart:     "gpu-operator",
 					Namespace: "gpu-operator",
 				},
 			},
+			ConfigName: DefaultConfigName,
 		}
 		// Use a unique controller name to avoid conflicts
 		Expect(reconciler.SetupWithManager(mgr)).To(Succeed())
@@ -131,7 +133,7 @@ var _ = Describe("Channel Controller (EnvTest)", func() {
 				bundle := &unstructured.Unstructured{}
 				bundle.SetGroupVersionKind(testBundleGVK)
 				err := testEnv.GetClient().Get(ctx,
-					types.NamespacedName{Name: "rmc-nvidia-stack", Namespace: "cattle-fleet-system"}, bundle)
+					types.NamespacedName{Name: "rmc-nvidia-stable", Namespace: "cattle-fleet-system"}, bundle)
 				return err == nil
 			}, 5*time.Second, 500*time.Millisecond).Should(BeTrue())
 
@@ -139,7 +141,7 @@ var _ = Describe("Channel Controller (EnvTest)", func() {
 			bundle := &unstructured.Unstructured{}
 			bundle.SetGroupVersionKind(testBundleGVK)
 			Expect(testEnv.GetClient().Get(ctx,
-				types.NamespacedName{Name: "rmc-nvidia-stack", Namespace: "cattle-fleet-system"}, bundle)).To(Succeed())
+				types.NamespacedName{Name: "rmc-nvidia-stable", Namespace: "cattle-fleet-system"}, bundle)).To(Succeed())
 
 			Expect(bundle.GetLabels()).To(HaveKeyWithValue("multi.suse.io/vendor", "nvidia"))
 			Expect(bundle.GetLabels()).To(HaveKeyWithValue("multi.suse.io/channel", "stable"))
@@ -173,14 +175,14 @@ var _ = Describe("Channel Controller (EnvTest)", func() {
 				bundle := &unstructured.Unstructured{}
 				bundle.SetGroupVersionKind(testBundleGVK)
 				err := testEnv.GetClient().Get(ctx,
-					types.NamespacedName{Name: "rmc-nvidia-stack", Namespace: "cattle-fleet-system"}, bundle)
+					types.NamespacedName{Name: "rmc-nvidia-ready", Namespace: "cattle-fleet-system"}, bundle)
 				return err == nil
 			}, 5*time.Second, 500*time.Millisecond).Should(BeTrue())
 
 			// Create a BundleDeployment with Ready status
 			bundleDeployment := &unstructured.Unstructured{}
 			bundleDeployment.SetGroupVersionKind(testBdGVK)
-			bundleDeployment.SetName("rmc-nvidia-stack-bd-test")
+			bundleDeployment.SetName("rmc-nvidia-ready-bd-test")
 			bundleDeployment.SetNamespace("cattle-fleet-system")
 			bundleDeployment.SetLabels(map[string]string{
 				"multi.suse.io/owner":  "nvidia-ready",
@@ -218,14 +220,14 @@ var _ = Describe("Channel Controller (EnvTest)", func() {
 				bundle := &unstructured.Unstructured{}
 				bundle.SetGroupVersionKind(testBundleGVK)
 				err := testEnv.GetClient().Get(ctx,
-					types.NamespacedName{Name: "rmc-nvidia-stack", Namespace: "cattle-fleet-system"}, bundle)
+					types.NamespacedName{Name: "rmc-nvidia-failed", Namespace: "cattle-fleet-system"}, bundle)
 				return err == nil
 			}, 5*time.Second, 500*time.Millisecond).Should(BeTrue())
 
 			// Create a BundleDeployment with Failed status
 			bundleDeployment := &unstructured.Unstructured{}
 			bundleDeployment.SetGroupVersionKind(testBdGVK)
-			bundleDeployment.SetName("rmc-nvidia-stack-bd-fail")
+			bundleDeployment.SetName("rmc-nvidia-failed-bd-fail")
 			bundleDeployment.SetNamespace("cattle-fleet-system")
 			bundleDeployment.SetLabels(map[string]string{
 				"multi.suse.io/owner":  "nvidia-failed",
@@ -243,6 +245,179 @@ var _ = Describe("Channel Controller (EnvTest)", func() {
 			}, 10*time.Second, 1*time.Second).Should(Equal("Failed"))
 		})
 
+		It("should report per-cluster rollout counts instead of completing on the first ready cluster", func() {
+			channel := &multisuseiov1alpha1.Channel{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "nvidia-partial",
+				},
+				Spec: multisuseiov1alpha1.ChannelSpec{
+					Vendor:  "nvidia",
+					Channel: "stable",
+					ClusterSelector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"kubernetes.io/os": "linux"},
+					},
+				},
+			}
+			Expect(testEnv.GetClient().Create(ctx, channel)).To(Succeed())
+
+			for cluster, ready := range map[string]bool{"gpu-a": true, "gpu-b": false} {
+				bundleDeployment := &unstructured.Unstructured{}
+				bundleDeployment.SetGroupVersionKind(testBdGVK)
+				bundleDeployment.SetName("rmc-nvidia-partial-" + cluster)
+				bundleDeployment.SetNamespace("cattle-fleet-system")
+				bundleDeployment.SetLabels(map[string]string{
+					"multi.suse.io/owner":     "nvidia-partial",
+					"multi.suse.io/vendor":    "nvidia",
+					"fleet.cattle.io/cluster": cluster,
+				})
+				state := "WaitApplied"
+				if ready {
+					state = "Ready"
+				}
+				Expect(unstructured.SetNestedField(bundleDeployment.Object, ready, "status", "ready")).To(Succeed())
+				Expect(unstructured.SetNestedField(bundleDeployment.Object, state, "status", "display", "state")).To(Succeed())
+				Expect(testEnv.GetClient().Create(ctx, bundleDeployment)).To(Succeed())
+			}
+
+			Eventually(func() multisuseiov1alpha1.ChannelStatus {
+				fetchedChannel := &multisuseiov1alpha1.Channel{}
+				_ = testEnv.GetClient().Get(ctx, types.NamespacedName{Name: "nvidia-partial"}, fetchedChannel)
+				return fetchedChannel.Status
+			}, 10*time.Second, 1*time.Second).Should(And(
+				HaveField("Phase", Equal("RollingOut")),
+				HaveField("DesiredClusters", BeEquivalentTo(2)),
+				HaveField("ReadyClusters", BeEquivalentTo(1)),
+				HaveField("PendingClusters", BeEquivalentTo(1)),
+				HaveField("ClusterStates", HaveLen(2)),
+			))
+		})
+
+		It("should report Paused and leave the Bundle untouched while paused", func() {
+			channel := &multisuseiov1alpha1.Channel{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "nvidia-paused",
+				},
+				Spec: multisuseiov1alpha1.ChannelSpec{
+					Vendor:  "nvidia",
+					Channel: "stable",
+					Paused:  true,
+					ClusterSelector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"kubernetes.io/os": "linux"},
+					},
+				},
+			}
+			Expect(testEnv.GetClient().Create(ctx, channel)).To(Succeed())
+
+			Eventually(func() string {
+				fetchedChannel := &multisuseiov1alpha1.Channel{}
+				_ = testEnv.GetClient().Get(ctx, types.NamespacedName{Name: "nvidia-paused"}, fetchedChannel)
+				return fetchedChannel.Status.Phase
+			}, 10*time.Second, 1*time.Second).Should(Equal("Paused"))
+
+			Consistently(func() bool {
+				bundle := &unstructured.Unstructured{}
+				bundle.SetGroupVersionKind(testBundleGVK)
+				err := testEnv.GetClient().Get(ctx,
+					types.NamespacedName{Name: "rmc-nvidia-paused", Namespace: "cattle-fleet-system"}, bundle)
+				return err == nil
+			}, 2*time.Second, 500*time.Millisecond).Should(BeFalse())
+		})
+
+		It("should deploy from the vendor source of the MultiComputeConfig", func() {
+			config := &multisuseiov1alpha1.MultiComputeConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: DefaultConfigName},
+				Spec: multisuseiov1alpha1.MultiComputeConfigSpec{
+					VendorSources: map[string]multisuseiov1alpha1.VendorSource{
+						"nvidia": {
+							Repo:      "https://charts.mirror.internal/nvidia",
+							Chart:     "gpu-operator",
+							Namespace: "gpu-operator",
+						},
+					},
+				},
+			}
+			Expect(testEnv.GetClient().Create(ctx, config)).To(Succeed())
+
+			channel := &multisuseiov1alpha1.Channel{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "nvidia-mirror",
+				},
+				Spec: multisuseiov1alpha1.ChannelSpec{
+					Vendor:  "nvidia",
+					Channel: "stable",
+				},
+			}
+			Expect(testEnv.GetClient().Create(ctx, channel)).To(Succeed())
+
+			// Verify the Channel reports the overriding source
+			Eventually(func() string {
+				fetchedChannel := &multisuseiov1alpha1.Channel{}
+				_ = testEnv.GetClient().Get(ctx, types.NamespacedName{Name: "nvidia-mirror"}, fetchedChannel)
+				if fetchedChannel.Status.VendorSource == nil {
+					return ""
+				}
+				return fetchedChannel.Status.VendorSource.Origin
+			}, 5*time.Second, 500*time.Millisecond).Should(Equal("MultiComputeConfig/" + DefaultConfigName))
+
+			// Removing the override moves the Channel back to the default source
+			Expect(testEnv.GetClient().Delete(ctx, config)).To(Succeed())
+			Eventually(func() string {
+				fetchedChannel := &multisuseiov1alpha1.Channel{}
+				_ = testEnv.GetClient().Get(ctx, types.NamespacedName{Name: "nvidia-mirror"}, fetchedChannel)
+				if fetchedChannel.Status.VendorSource == nil {
+					return ""
+				}
+				return fetchedChannel.Status.VendorSource.Repo
+			}, 5*time.Second, 500*time.Millisecond).Should(Equal("https://nvidia.github.io/helm-charts"))
+		})
+
+		It("should merge channel values and deploy values overrides as additional targets", func() {
+			channel := &multisuseiov1alpha1.Channel{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "nvidia-values",
+				},
+				Spec: multisuseiov1alpha1.ChannelSpec{
+					Vendor:  "nvidia",
+					Channel: "stable",
+					ClusterSelector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"kubernetes.io/os": "linux"},
+					},
+					Values: &apiextensionsv1.JSON{Raw: []byte(`{"mig":{"strategy":"single"}}`)},
+					ValuesOverrides: []multisuseiov1alpha1.ValuesOverride{{
+						ClusterSelector: metav1.LabelSelector{
+							MatchLabels: map[string]string{"driver": "preinstalled"},
+						},
+						Values: apiextensionsv1.JSON{Raw: []byte(`{"driver":{"enabled":false}}`)},
+					}},
+				},
+			}
+			Expect(testEnv.GetClient().Create(ctx, channel)).To(Succeed())
+
+			var targets []interface{}
+			Eventually(func() int {
+				bundle := &unstructured.Unstructured{}
+				bundle.SetGroupVersionKind(testBundleGVK)
+				_ = testEnv.GetClient().Get(ctx,
+					types.NamespacedName{Name: "rmc-nvidia-values", Namespace: "cattle-fleet-system"}, bundle)
+				targets, _, _ = unstructured.NestedSlice(bundle.Object, "spec", "targets")
+				return len(targets)
+			}, 5*time.Second, 500*time.Millisecond).Should(Equal(2))
+
+			// The override target precedes the channel target, Fleet deploying the first match
+			override := targets[0].(map[string]interface{})
+			strategy, _, _ := unstructured.NestedString(override, "helm", "values", "mig", "strategy")
+			Expect(strategy).To(Equal("single"))
+			enabled, found, _ := unstructured.NestedBool(override, "helm", "values", "driver", "enabled")
+			Expect(found).To(BeTrue())
+			Expect(enabled).To(BeFalse())
+
+			base := targets[1].(map[string]interface{})
+			strategy, _, _ = unstructured.NestedString(base, "helm", "values", "mig", "strategy")
+			Expect(strategy).To(Equal("single"))
+			_, found, _ = unstructured.NestedFieldNoCopy(base, "helm", "values", "driver")
+			Expect(found).To(BeFalse())
+		})
+
 		It("should handle invalid vendor specification", func() {
 			channel := &multisuseiov1alpha1.Channel{
 				ObjectMeta: metav1.ObjectMeta{
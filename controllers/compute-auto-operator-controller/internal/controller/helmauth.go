@@ -0,0 +1,148 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	multisuseiov1alpha1 "github.com/suse/rancher-multi-compute/api/multi.suse.io/v1alpha1"
+	"github.com/suse/rancher-multi-compute/internal/fleetutil"
+	"github.com/suse/rancher-multi-compute/internal/vendors"
+)
+
+const (
+	// helmSecretSuffix names the Secret holding the chart repository credentials of a Bundle
+	helmSecretSuffix = "-helm"
+	// helmCACertsKey is the Secret key Fleet reads the repository CA bundle from
+	helmCACertsKey = "cacerts"
+)
+
+// helmCredentialKeys are the keys copied from the referenced credentials Secret
+var helmCredentialKeys = []string{corev1.BasicAuthUsernameKey, corev1.BasicAuthPasswordKey, helmCACertsKey}
+
+// helmSecretName returns the name of the Secret holding the chart repository credentials of a Channel
+func helmSecretName(ch *multisuseiov1alpha1.Channel) string {
+	return bundleName(ch) + helmSecretSuffix
+}
+
+// reconcileHelmAuth maintains the Secret Fleet agents use to pull the vendor chart, combining the
+// referenced credentials with the CA bundle, and returns the Bundle's Helm app options. Nil options
+// leave chart pulling to Fleet's defaults.
+func (r *ChannelReconciler) reconcileHelmAuth(ctx context.Context, ch *multisuseiov1alpha1.Channel, source vendors.Source) (*fleetutil.HelmAppOptions, error) {
+	name := helmSecretName(ch)
+	if !source.Authenticated() {
+		if err := r.deleteHelmSecret(ctx, name); err != nil {
+			return nil, err
+		}
+		if source.InsecureSkipTLSVerify {
+			return &fleetutil.HelmAppOptions{InsecureSkipTLSVerify: true}, nil
+		}
+		return nil, nil
+	}
+
+	data := map[string][]byte{}
+	if source.HelmSecretName != "" {
+		credentials := &corev1.Secret{}
+		key := types.NamespacedName{Namespace: fleetSystemNamespace, Name: source.HelmSecretName}
+		if err := r.Get(ctx, key, credentials); err != nil {
+			return nil, fmt.Errorf("failed to get Helm repository Secret %s: %w", key, err)
+		}
+		for _, k := range helmCredentialKeys {
+			if v, ok := credentials.Data[k]; ok {
+				data[k] = v
+			}
+		}
+		if len(data[corev1.BasicAuthPasswordKey]) == 0 {
+			return nil, fmt.Errorf("helm repository Secret %s has no %s key", key, corev1.BasicAuthPasswordKey)
+		}
+	}
+	if source.CABundle != "" {
+		data[helmCACertsKey] = []byte(source.CABundle)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: fleetSystemNamespace,
+			Labels: map[string]string{
+				partOfLabelKey: partOfLabelValue,
+				ownerLabelKey:  ch.Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "multi.suse.io/v1alpha1",
+				Kind:       "Channel",
+				Name:       ch.Name,
+				UID:        ch.UID,
+			}},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: data,
+	}
+
+	current := &corev1.Secret{}
+	err := r.Get(ctx, client.ObjectKeyFromObject(secret), current)
+	switch {
+	case errors.IsNotFound(err):
+		if err := r.Create(ctx, secret); err != nil {
+			return nil, fmt.Errorf("failed to create Helm repository Secret %s: %w", name, err)
+		}
+	case err != nil:
+		return nil, fmt.Errorf("failed to get Helm repository Secret %s: %w", name, err)
+	case !equality.Semantic.DeepEqual(current.Data, secret.Data):
+		current.Data = secret.Data
+		current.SetLabels(secret.GetLabels())
+		if err := r.Update(ctx, current); err != nil {
+			return nil, fmt.Errorf("failed to update Helm repository Secret %s: %w", name, err)
+		}
+	}
+
+	return &fleetutil.HelmAppOptions{
+		SecretName:            name,
+		InsecureSkipTLSVerify: source.InsecureSkipTLSVerify,
+	}, nil
+}
+
+// deleteHelmSecret deletes the Helm repository Secret of a Channel no longer needing one
+func (r *ChannelReconciler) deleteHelmSecret(ctx context.Context, name string) error {
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: fleetSystemNamespace, Name: name}, secret); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get Helm repository Secret %s: %w", name, err)
+	}
+	if err := r.Delete(ctx, secret); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete Helm repository Secret %s: %w", name, err)
+	}
+	return nil
+}
+
+// isHelmCredentials reports whether obj is a Secret in the Fleet namespace, where credentials are referenced from
+func isHelmCredentials(obj client.Object) bool {
+	return obj.GetNamespace() == fleetSystemNamespace
+}
+
+// channelsForHelmCredentials enqueues the Channels deploying with a changed credentials Secret
+func (r *ChannelReconciler) channelsForHelmCredentials(ctx context.Context, obj client.Object) []reconcile.Request {
+	channels := &multisuseiov1alpha1.ChannelList{}
+	if err := r.List(ctx, channels); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list Channels for Helm repository Secret", "secret", obj.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, channel := range channels.Items {
+		if source := channel.Status.VendorSource; source != nil && source.HelmSecretName == obj.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&channel)})
+		}
+	}
+	return requests
+}
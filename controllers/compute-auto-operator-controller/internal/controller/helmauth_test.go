@@ -0,0 +1,136 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	multisuseiov1alpha1 "github.com/suse/rancher-multi-compute/api/multi.suse.io/v1alpha1"
+	"github.com/suse/rancher-multi-compute/internal/fleetutil"
+	"github.com/suse/rancher-multi-compute/internal/testutil"
+	"github.com/suse/rancher-multi-compute/internal/vendors"
+)
+
+// helmAuthReconciler returns a reconciler whose fake client holds the credentials Secret
+func helmAuthReconciler(t *testing.T, credentials map[string]string) *ChannelReconciler {
+	c := testutil.NewFakeClient(t, credentialsSecret(credentials))
+	return &ChannelReconciler{Client: c, Scheme: c.Scheme()}
+}
+
+// helmAuthChannel returns a Channel deploying from a private chart repository
+func helmAuthChannel() *multisuseiov1alpha1.Channel {
+	return &multisuseiov1alpha1.Channel{
+		ObjectMeta: metav1.ObjectMeta{Name: "nvidia-stable", UID: "5678"},
+		Spec:       multisuseiov1alpha1.ChannelSpec{Vendor: "nvidia", Channel: "stable"},
+	}
+}
+
+func credentialsSecret(data map[string]string) *corev1.Secret {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "registry-credentials", Namespace: fleetSystemNamespace},
+		Data:       map[string][]byte{},
+	}
+	for k, v := range data {
+		secret.Data[k] = []byte(v)
+	}
+	return secret
+}
+
+// helmSecret returns the Helm repository Secret of the Channel, nil if there is none
+func helmSecret(t *testing.T, r *ChannelReconciler, channel *multisuseiov1alpha1.Channel) *corev1.Secret {
+	secret := &corev1.Secret{}
+	err := r.Get(context.Background(), client.ObjectKey{Namespace: fleetSystemNamespace, Name: helmSecretName(channel)}, secret)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	require.NoError(t, err)
+	return secret
+}
+
+func TestReconcileHelmAuth_CopiesCredentials(t *testing.T) {
+	r := helmAuthReconciler(t, map[string]string{
+		"username": "robot",
+		"password": "s3cret",
+		"token":    "not copied",
+	})
+	channel := helmAuthChannel()
+
+	options, err := r.reconcileHelmAuth(context.Background(), channel, vendors.Source{HelmSecretName: "registry-credentials"})
+	require.NoError(t, err)
+	assert.Equal(t, &fleetutil.HelmAppOptions{SecretName: "rmc-nvidia-stable-helm"}, options)
+
+	secret := helmSecret(t, r, channel)
+	require.NotNil(t, secret)
+	assert.Equal(t, map[string][]byte{"username": []byte("robot"), "password": []byte("s3cret")}, secret.Data)
+	assert.Equal(t, channel.Name, secret.Labels[ownerLabelKey])
+	require.Len(t, secret.OwnerReferences, 1)
+	assert.Equal(t, "Channel", secret.OwnerReferences[0].Kind)
+	assert.Equal(t, channel.UID, secret.OwnerReferences[0].UID)
+
+	// Rotated credentials are copied over
+	credentials := credentialsSecret(map[string]string{"username": "robot", "password": "rotated"})
+	require.NoError(t, r.Update(context.Background(), credentials))
+	_, err = r.reconcileHelmAuth(context.Background(), channel, vendors.Source{HelmSecretName: "registry-credentials"})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("rotated"), helmSecret(t, r, channel).Data["password"])
+}
+
+func TestReconcileHelmAuth_MissingPassword(t *testing.T) {
+	r := helmAuthReconciler(t, map[string]string{"username": "robot"})
+	channel := helmAuthChannel()
+
+	_, err := r.reconcileHelmAuth(context.Background(), channel, vendors.Source{HelmSecretName: "registry-credentials"})
+	assert.ErrorContains(t, err, "has no password key")
+	assert.Nil(t, helmSecret(t, r, channel))
+
+	_, err = r.reconcileHelmAuth(context.Background(), channel, vendors.Source{HelmSecretName: "missing"})
+	assert.ErrorContains(t, err, "failed to get Helm repository Secret")
+}
+
+func TestReconcileHelmAuth_CABundle(t *testing.T) {
+	r := helmAuthReconciler(t, map[string]string{
+		"username": "robot",
+		"password": "s3cret",
+		"cacerts":  "referenced CA",
+	})
+	channel := helmAuthChannel()
+	source := vendors.Source{HelmSecretName: "registry-credentials", CABundle: "configured CA", InsecureSkipTLSVerify: true}
+
+	options, err := r.reconcileHelmAuth(context.Background(), channel, source)
+	require.NoError(t, err)
+	assert.Equal(t, &fleetutil.HelmAppOptions{SecretName: "rmc-nvidia-stable-helm", InsecureSkipTLSVerify: true}, options)
+	// The configured CA bundle takes precedence over the referenced one
+	assert.Equal(t, map[string][]byte{
+		"username": []byte("robot"),
+		"password": []byte("s3cret"),
+		"cacerts":  []byte("configured CA"),
+	}, helmSecret(t, r, channel).Data)
+
+	// A CA bundle alone needs no credentials
+	_, err = r.reconcileHelmAuth(context.Background(), channel, vendors.Source{CABundle: "configured CA"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"cacerts": []byte("configured CA")}, helmSecret(t, r, channel).Data)
+}
+
+func TestReconcileHelmAuth_Removed(t *testing.T) {
+	r := helmAuthReconciler(t, map[string]string{"username": "robot", "password": "s3cret"})
+	channel := helmAuthChannel()
+	_, err := r.reconcileHelmAuth(context.Background(), channel, vendors.Source{HelmSecretName: "registry-credentials"})
+	require.NoError(t, err)
+	require.NotNil(t, helmSecret(t, r, channel))
+
+	options, err := r.reconcileHelmAuth(context.Background(), channel, vendors.Source{})
+	require.NoError(t, err)
+	assert.Nil(t, options)
+	assert.Nil(t, helmSecret(t, r, channel))
+
+	options, err = r.reconcileHelmAuth(context.Background(), channel, vendors.Source{InsecureSkipTLSVerify: true})
+	require.NoError(t, err)
+	assert.Equal(t, &fleetutil.HelmAppOptions{InsecureSkipTLSVerify: true}, options)
+}
@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	multisuseiov1alpha1 "github.com/suse/rancher-multi-compute/api/multi.suse.io/v1alpha1"
+	"github.com/suse/rancher-multi-compute/internal/helmvalues"
+	"github.com/suse/rancher-multi-compute/internal/vendors"
+	"github.com/suse/rancher-multi-compute/internal/versions"
+)
+
+// maxRevisionHistory bounds the revisions kept in status
+const maxRevisionHistory = 10
+
+// revisionPins returns the pins of the revision requested by spec.rollbackTo. A revision that
+// already restored the requested one also matches, so the request survives history trimming.
+func revisionPins(channel *multisuseiov1alpha1.Channel) (versions.Pins, error) {
+	target := *channel.Spec.RollbackTo
+	history := channel.Status.History
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Revision == target || history[i].RollbackOf == target {
+			return fromPinnedVersion(history[i].Version), nil
+		}
+	}
+	return versions.Pins{}, fmt.Errorf("revision %d not found in channel history", target)
+}
+
+// recordRevision appends a revision to the Channel history when the applied pins or Helm values
+// differ from the latest revision. A latest revision still rolling out is marked superseded.
+func (r *ChannelReconciler) recordRevision(channel *multisuseiov1alpha1.Channel, source vendors.Source, pins versions.Pins, values helmvalues.Values, sourceRevision string) error {
+	pinValues, err := source.HelmValues(pins)
+	if err != nil {
+		return fmt.Errorf("failed to render Helm values: %w", err)
+	}
+	hash, err := values.Hash(pinValues)
+	if err != nil {
+		return fmt.Errorf("failed to hash Helm values: %w", err)
+	}
+
+	revision := multisuseiov1alpha1.ChannelRevision{
+		Revision:       1,
+		Version:        toPinnedVersion(pins),
+		ValuesHash:     hash,
+		SourceRevision: sourceRevision,
+		Time:           metav1.Now(),
+		Outcome:        multisuseiov1alpha1.RevisionRollingOut,
+	}
+	if channel.Spec.RollbackTo != nil {
+		revision.RollbackOf = *channel.Spec.RollbackTo
+	}
+
+	history := channel.Status.History
+	if n := len(history); n > 0 {
+		latest := &history[n-1]
+		if latest.Version == revision.Version && latest.ValuesHash == revision.ValuesHash {
+			return nil
+		}
+		if latest.Outcome == multisuseiov1alpha1.RevisionRollingOut {
+			latest.Outcome = multisuseiov1alpha1.RevisionSuperseded
+		}
+		revision.Revision = latest.Revision + 1
+	}
+
+	history = append(history, revision)
+	if len(history) > maxRevisionHistory {
+		history = history[len(history)-maxRevisionHistory:]
+	}
+	channel.Status.History = history
+	return nil
+}
+
+// setRevisionOutcome reports the channel phase as the outcome of the latest revision. The revision
+// keeps rolling out until the BundleDeployments run its release, as they report the readiness of the
+// previous revision until Fleet applies it. Rolled back and superseded revisions keep their outcome.
+func setRevisionOutcome(channel *multisuseiov1alpha1.Channel, phase string, release releaseState) {
+	history := channel.Status.History
+	if len(history) == 0 {
+		return
+	}
+	latest := &history[len(history)-1]
+	switch latest.Outcome {
+	case multisuseiov1alpha1.RevisionRolledBack, multisuseiov1alpha1.RevisionSuperseded:
+		return
+	}
+
+	switch {
+	case phase == "Completed" && release.Verified:
+		latest.Outcome = multisuseiov1alpha1.RevisionCompleted
+	case phase == "Failed" && release.Failing:
+		latest.Outcome = multisuseiov1alpha1.RevisionFailed
+	default:
+		latest.Outcome = multisuseiov1alpha1.RevisionRollingOut
+	}
+}
@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	multisuseiov1alpha1 "github.com/suse/rancher-multi-compute/api/multi.suse.io/v1alpha1"
+	"github.com/suse/rancher-multi-compute/internal/helmvalues"
+	"github.com/suse/rancher-multi-compute/internal/vendors"
+	"github.com/suse/rancher-multi-compute/internal/versions"
+)
+
+func TestRecordRevision(t *testing.T) {
+	r := &ChannelReconciler{}
+	channel := &multisuseiov1alpha1.Channel{}
+
+	require.NoError(t, r.recordRevision(channel, vendors.Source{}, goodPins, helmvalues.Values{}, "abc"))
+	require.Len(t, channel.Status.History, 1)
+	first := channel.Status.History[0]
+	assert.Equal(t, int64(1), first.Revision)
+	assert.Equal(t, toPinnedVersion(goodPins), first.Version)
+	assert.Equal(t, "abc", first.SourceRevision)
+	assert.NotEmpty(t, first.ValuesHash)
+	assert.Equal(t, multisuseiov1alpha1.RevisionRollingOut, first.Outcome)
+
+	// The same pins and values are not recorded twice
+	require.NoError(t, r.recordRevision(channel, vendors.Source{}, goodPins, helmvalues.Values{}, "def"))
+	assert.Len(t, channel.Status.History, 1)
+
+	// A revision still rolling out is superseded by the next one
+	require.NoError(t, r.recordRevision(channel, vendors.Source{}, newPins, helmvalues.Values{}, "def"))
+	require.Len(t, channel.Status.History, 2)
+	assert.Equal(t, multisuseiov1alpha1.RevisionSuperseded, channel.Status.History[0].Outcome)
+	assert.Equal(t, int64(2), channel.Status.History[1].Revision)
+
+	// Changing the values alone records a revision
+	overridden := helmvalues.Values{Base: map[string]interface{}{"driver": map[string]interface{}{"enabled": false}}}
+	require.NoError(t, r.recordRevision(channel, vendors.Source{}, newPins, overridden, "def"))
+	require.Len(t, channel.Status.History, 3)
+	assert.NotEqual(t, channel.Status.History[1].ValuesHash, channel.Status.History[2].ValuesHash)
+}
+
+func TestRecordRevision_Trim(t *testing.T) {
+	r := &ChannelReconciler{}
+	channel := &multisuseiov1alpha1.Channel{}
+
+	for i := 0; i < maxRevisionHistory+3; i++ {
+		pins := versions.Pins{OperatorTag: fmt.Sprintf("v1.%d", i), RuntimeTag: "r1", ChartVersion: fmt.Sprintf("v1.%d", i)}
+		require.NoError(t, r.recordRevision(channel, vendors.Source{}, pins, helmvalues.Values{}, ""))
+	}
+	history := channel.Status.History
+	require.Len(t, history, maxRevisionHistory)
+	assert.Equal(t, int64(4), history[0].Revision)
+	assert.Equal(t, int64(maxRevisionHistory+3), history[len(history)-1].Revision)
+}
+
+func TestRecordRevision_RollbackTo(t *testing.T) {
+	r := &ChannelReconciler{}
+	channel := rollbackChannel()
+	revision := int64(1)
+	channel.Spec.RollbackTo = &revision
+
+	require.NoError(t, r.recordRevision(channel, vendors.Source{}, goodPins, helmvalues.Values{}, ""))
+	require.Len(t, channel.Status.History, 3)
+	latest := channel.Status.History[2]
+	assert.Equal(t, int64(3), latest.Revision)
+	assert.Equal(t, int64(1), latest.RollbackOf)
+	assert.Equal(t, toPinnedVersion(goodPins), latest.Version)
+	assert.Equal(t, multisuseiov1alpha1.RevisionSuperseded, channel.Status.History[1].Outcome)
+}
+
+func TestRevisionPins(t *testing.T) {
+	channel := rollbackChannel()
+	revision := int64(2)
+	channel.Spec.RollbackTo = &revision
+
+	pins, err := revisionPins(channel)
+	require.NoError(t, err)
+	assert.Equal(t, newPins, pins)
+
+	// A revision restoring a trimmed one stands in for it
+	revision = 1
+	channel.Status.History = []multisuseiov1alpha1.ChannelRevision{
+		{Revision: 12, Version: toPinnedVersion(goodPins), RollbackOf: 1},
+		{Revision: 13, Version: toPinnedVersion(newPins)},
+	}
+	pins, err = revisionPins(channel)
+	require.NoError(t, err)
+	assert.Equal(t, goodPins, pins)
+
+	revision = 5
+	_, err = revisionPins(channel)
+	assert.ErrorContains(t, err, "revision 5 not found")
+}
+
+func TestSetRevisionOutcome(t *testing.T) {
+	latest := func(channel *multisuseiov1alpha1.Channel) string {
+		return channel.Status.History[len(channel.Status.History)-1].Outcome
+	}
+
+	// The previous release being ready does not complete the new revision
+	channel := rollbackChannel()
+	setRevisionOutcome(channel, "Completed", releaseState{})
+	assert.Equal(t, multisuseiov1alpha1.RevisionRollingOut, latest(channel))
+	setRevisionOutcome(channel, "Completed", releaseState{Verified: true})
+	assert.Equal(t, multisuseiov1alpha1.RevisionCompleted, latest(channel))
+
+	channel = rollbackChannel()
+	setRevisionOutcome(channel, "Failed", releaseState{})
+	assert.Equal(t, multisuseiov1alpha1.RevisionRollingOut, latest(channel))
+	setRevisionOutcome(channel, "Failed", releaseState{Failing: true})
+	assert.Equal(t, multisuseiov1alpha1.RevisionFailed, latest(channel))
+
+	channel.Status.History[1].Outcome = multisuseiov1alpha1.RevisionRolledBack
+	setRevisionOutcome(channel, "Completed", releaseState{Verified: true})
+	assert.Equal(t, multisuseiov1alpha1.RevisionRolledBack, latest(channel))
+}
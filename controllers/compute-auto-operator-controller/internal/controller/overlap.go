@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	multisuseiov1alpha1 "github.com/suse/rancher-multi-compute/api/multi.suse.io/v1alpha1"
+	"github.com/suse/rancher-multi-compute/internal/rollout"
+)
+
+const (
+	clusterOverlapConditionType = "ClusterOverlap"
+	// maxOverlapClusters bounds the clusters named per overlapping Channel in the condition message
+	maxOverlapClusters = 5
+)
+
+// channelOverlap lists the clusters a Channel shares with another Channel of the same vendor
+type channelOverlap struct {
+	channel  string
+	priority int32
+	clusters []string
+	// wins reports whether the reconciled Channel deploys to the shared clusters
+	wins bool
+}
+
+// outranks reports whether Channel a wins the clusters it shares with Channel b
+func outranks(a, b *multisuseiov1alpha1.Channel) bool {
+	if a.Spec.Priority != b.Spec.Priority {
+		return a.Spec.Priority > b.Spec.Priority
+	}
+	return a.Name < b.Name
+}
+
+// findOverlaps returns the other Channels of the same vendor selecting some of the Channel's clusters
+func (r *ChannelReconciler) findOverlaps(ctx context.Context, channel *multisuseiov1alpha1.Channel) ([]channelOverlap, error) {
+	channels := &multisuseiov1alpha1.ChannelList{}
+	if err := r.List(ctx, channels); err != nil {
+		return nil, fmt.Errorf("failed to list Channels: %w", err)
+	}
+	clusters, err := r.fleetClusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	selected, err := rollout.Select(channel.Spec.ClusterSelector, clusters)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cluster selector: %w", err)
+	}
+	own := map[string]bool{}
+	for _, name := range selected {
+		own[name] = true
+	}
+
+	var overlaps []channelOverlap
+	for i := range channels.Items {
+		other := &channels.Items[i]
+		if other.Name == channel.Name || !other.DeletionTimestamp.IsZero() ||
+			!strings.EqualFold(other.Spec.Vendor, channel.Spec.Vendor) {
+			continue
+		}
+		theirs, err := rollout.Select(other.Spec.ClusterSelector, clusters)
+		if err != nil {
+			// The other Channel reports its own invalid selector
+			continue
+		}
+		overlap := channelOverlap{
+			channel:  other.Name,
+			priority: other.Spec.Priority,
+			wins:     outranks(channel, other),
+		}
+		for _, name := range theirs {
+			if own[name] {
+				overlap.clusters = append(overlap.clusters, name)
+			}
+		}
+		if len(overlap.clusters) > 0 {
+			overlaps = append(overlaps, overlap)
+		}
+	}
+	sort.Slice(overlaps, func(i, j int) bool { return overlaps[i].channel < overlaps[j].channel })
+	return overlaps, nil
+}
+
+// resolveOverlaps sets the ClusterOverlap condition of the Channel and returns the selected
+// clusters it must leave to higher-priority Channels of the same vendor, sorted by name.
+func (r *ChannelReconciler) resolveOverlaps(ctx context.Context, channel *multisuseiov1alpha1.Channel) ([]string, error) {
+	overlaps, err := r.findOverlaps(ctx, channel)
+	if err != nil {
+		return nil, err
+	}
+
+	condition := metav1.Condition{
+		Type:    clusterOverlapConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  "NoOverlap",
+		Message: fmt.Sprintf("No other %s Channel selects the same clusters", strings.ToLower(channel.Spec.Vendor)),
+	}
+	excluded := map[string]bool{}
+	if len(overlaps) > 0 {
+		var lost, won []string
+		for _, o := range overlaps {
+			part := fmt.Sprintf("%s (priority %d): %s", o.channel, o.priority, formatClusters(o.clusters))
+			if o.wins {
+				won = append(won, part)
+				continue
+			}
+			lost = append(lost, part)
+			for _, name := range o.clusters {
+				excluded[name] = true
+			}
+		}
+
+		var parts []string
+		if len(lost) > 0 {
+			parts = append(parts, "excluded in favor of "+strings.Join(lost, "; "))
+		}
+		if len(won) > 0 {
+			parts = append(parts, "deployed instead of "+strings.Join(won, "; "))
+		}
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "HigherPriority"
+		if len(lost) > 0 {
+			condition.Reason = "LowerPriority"
+		}
+		condition.Message = fmt.Sprintf("Clusters shared with other %s Channels at priority %d, %s",
+			strings.ToLower(channel.Spec.Vendor), channel.Spec.Priority, strings.Join(parts, ", "))
+	}
+
+	previous := meta.FindStatusCondition(channel.Status.Conditions, clusterOverlapConditionType)
+	if r.Recorder != nil && condition.Status == metav1.ConditionTrue &&
+		(previous == nil || previous.Status != metav1.ConditionTrue || previous.Message != condition.Message) {
+		r.Recorder.Event(channel, corev1.EventTypeWarning, "ClusterOverlap", condition.Message)
+	}
+	meta.SetStatusCondition(&channel.Status.Conditions, condition)
+
+	var names []string
+	for name := range excluded {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// formatClusters joins cluster names, naming at most maxOverlapClusters of them
+func formatClusters(clusters []string) string {
+	if len(clusters) <= maxOverlapClusters {
+		return strings.Join(clusters, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(clusters[:maxOverlapClusters], ", "), len(clusters)-maxOverlapClusters)
+}
+
+// channelsOfVendor enqueues the other Channels of a changed Channel's vendor so they refresh their overlap condition
+func (r *ChannelReconciler) channelsOfVendor(ctx context.Context, obj client.Object) []reconcile.Request {
+	changed, ok := obj.(*multisuseiov1alpha1.Channel)
+	if !ok {
+		return nil
+	}
+	channels := &multisuseiov1alpha1.ChannelList{}
+	if err := r.List(ctx, channels); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, other := range channels.Items {
+		if other.Name != changed.Name && strings.EqualFold(other.Spec.Vendor, changed.Spec.Vendor) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&other)})
+		}
+	}
+	return requests
+}
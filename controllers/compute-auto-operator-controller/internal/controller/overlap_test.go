@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	multisuseiov1alpha1 "github.com/suse/rancher-multi-compute/api/multi.suse.io/v1alpha1"
+	"github.com/suse/rancher-multi-compute/internal/testutil"
+)
+
+func overlapChannel(name, vendor string, priority int32, selector map[string]string) *multisuseiov1alpha1.Channel {
+	return &multisuseiov1alpha1.Channel{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: multisuseiov1alpha1.ChannelSpec{
+			Vendor:          vendor,
+			Channel:         "stable",
+			Priority:        priority,
+			ClusterSelector: metav1.LabelSelector{MatchLabels: selector},
+		},
+	}
+}
+
+func labeledCluster(name string, labels map[string]string) *unstructured.Unstructured {
+	cluster := &unstructured.Unstructured{}
+	cluster.SetGroupVersionKind(clusterGVK)
+	cluster.SetNamespace("fleet-default")
+	cluster.SetName(name)
+	cluster.SetLabels(labels)
+	return cluster
+}
+
+// overlapObjects returns two NVIDIA Channels sharing the production cluster, an AMD Channel selecting
+// the same clusters, and the Fleet clusters
+func overlapObjects() []client.Object {
+	return []client.Object{
+		overlapChannel("nvidia-stable", "nvidia", 0, map[string]string{"gpu": "nvidia"}),
+		overlapChannel("nvidia-prod", "NVIDIA", 10, map[string]string{"tier": "prod"}),
+		overlapChannel("amd-stable", "amd", 100, map[string]string{"gpu": "nvidia"}),
+		labeledCluster("gpu-a", map[string]string{"gpu": "nvidia", "tier": "prod"}),
+		labeledCluster("gpu-b", map[string]string{"gpu": "nvidia"}),
+	}
+}
+
+// resolve runs resolveOverlaps for the named Channel and returns its excluded clusters and ClusterOverlap condition
+func resolve(t *testing.T, r *ChannelReconciler, name string) ([]string, *metav1.Condition) {
+	channel := &multisuseiov1alpha1.Channel{}
+	require.NoError(t, r.Get(context.Background(), client.ObjectKey{Name: name}, channel))
+	excluded, err := r.resolveOverlaps(context.Background(), channel)
+	require.NoError(t, err)
+	condition := meta.FindStatusCondition(channel.Status.Conditions, clusterOverlapConditionType)
+	require.NotNil(t, condition)
+	return excluded, condition
+}
+
+func TestOutranks(t *testing.T) {
+	low := overlapChannel("a-channel", "nvidia", 0, nil)
+	high := overlapChannel("b-channel", "nvidia", 10, nil)
+	assert.True(t, outranks(high, low))
+	assert.False(t, outranks(low, high))
+
+	// Equal priorities are broken by name
+	high.Spec.Priority = 0
+	assert.True(t, outranks(low, high))
+	assert.False(t, outranks(high, low))
+}
+
+func TestResolveOverlaps_LowerPriority(t *testing.T) {
+	c := testutil.NewFakeClient(t, overlapObjects()...)
+	r := &ChannelReconciler{Client: c, Scheme: c.Scheme()}
+	recorder := record.NewFakeRecorder(10)
+	r.Recorder = recorder
+
+	excluded, condition := resolve(t, r, "nvidia-stable")
+	assert.Equal(t, []string{"gpu-a"}, excluded)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, "LowerPriority", condition.Reason)
+	assert.Equal(t, "Clusters shared with other nvidia Channels at priority 0, excluded in favor of nvidia-prod (priority 10): gpu-a", condition.Message)
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "ClusterOverlap")
+}
+
+func TestResolveOverlaps_HigherPriority(t *testing.T) {
+	c := testutil.NewFakeClient(t, overlapObjects()...)
+	r := &ChannelReconciler{Client: c, Scheme: c.Scheme()}
+
+	excluded, condition := resolve(t, r, "nvidia-prod")
+	assert.Empty(t, excluded)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, "HigherPriority", condition.Reason)
+	assert.Equal(t, "Clusters shared with other nvidia Channels at priority 10, deployed instead of nvidia-stable (priority 0): gpu-a", condition.Message)
+}
+
+func TestResolveOverlaps_NameTieBreak(t *testing.T) {
+	c := testutil.NewFakeClient(t,
+		overlapChannel("a-channel", "nvidia", 5, map[string]string{"gpu": "nvidia"}),
+		overlapChannel("b-channel", "nvidia", 5, map[string]string{"gpu": "nvidia"}),
+		labeledCluster("gpu-a", map[string]string{"gpu": "nvidia"}),
+		labeledCluster("gpu-b", map[string]string{"gpu": "nvidia"}),
+	)
+	r := &ChannelReconciler{Client: c, Scheme: c.Scheme()}
+
+	excluded, condition := resolve(t, r, "a-channel")
+	assert.Empty(t, excluded)
+	assert.Equal(t, "HigherPriority", condition.Reason)
+
+	excluded, condition = resolve(t, r, "b-channel")
+	assert.Equal(t, []string{"gpu-a", "gpu-b"}, excluded)
+	assert.Equal(t, "LowerPriority", condition.Reason)
+}
+
+func TestResolveOverlaps_NoOverlap(t *testing.T) {
+	c := testutil.NewFakeClient(t, overlapObjects()...)
+	r := &ChannelReconciler{Client: c, Scheme: c.Scheme()}
+	recorder := record.NewFakeRecorder(10)
+	r.Recorder = recorder
+
+	// Channels of other vendors never overlap
+	excluded, condition := resolve(t, r, "amd-stable")
+	assert.Empty(t, excluded)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, "NoOverlap", condition.Reason)
+	assert.Empty(t, recorder.Events)
+}
+
+func TestFormatClusters(t *testing.T) {
+	assert.Equal(t, "gpu-a, gpu-b", formatClusters([]string{"gpu-a", "gpu-b"}))
+	assert.Equal(t, "c1, c2, c3, c4, c5", formatClusters([]string{"c1", "c2", "c3", "c4", "c5"}))
+	assert.Equal(t, "c1, c2, c3, c4, c5 and 2 more", formatClusters([]string{"c1", "c2", "c3", "c4", "c5", "c6", "c7"}))
+}
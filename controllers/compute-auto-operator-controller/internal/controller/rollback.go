@@ -0,0 +1,148 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	multisuseiov1alpha1 "github.com/suse/rancher-multi-compute/api/multi.suse.io/v1alpha1"
+	"github.com/suse/rancher-multi-compute/internal/drift"
+	"github.com/suse/rancher-multi-compute/internal/fleetutil"
+	"github.com/suse/rancher-multi-compute/internal/helmvalues"
+	"github.com/suse/rancher-multi-compute/internal/vendors"
+	"github.com/suse/rancher-multi-compute/internal/versions"
+)
+
+const rolledBackConditionType = "RolledBack"
+
+// effectivePins returns the pins to deploy: the last known-good pins while an automatic rollback
+// is in effect, the resolved pins otherwise. A rollback ends as soon as a new version is pinned.
+func (r *ChannelReconciler) effectivePins(ctx context.Context, channel *multisuseiov1alpha1.Channel, resolved versions.Pins) versions.Pins {
+	rb := channel.Status.Rollback
+	if rb == nil {
+		return resolved
+	}
+	if rb.From == toPinnedVersion(resolved) {
+		return fromPinnedVersion(rb.To)
+	}
+
+	log.FromContext(ctx).Info("New version pinned, ending rollback", "channel", channel.Name, "version", formatPins(toPinnedVersion(resolved)))
+	channel.Status.Rollback = nil
+	meta.RemoveStatusCondition(&channel.Status.Conditions, rolledBackConditionType)
+	return resolved
+}
+
+// releaseState reports how far the BundleDeployments of a Channel run the release of the applied pins
+type releaseState struct {
+	// Verified is set when every BundleDeployment is ready on the release
+	Verified bool
+	// Failing is set when a BundleDeployment of the release failed
+	Failing bool
+}
+
+// observeRelease checks the BundleDeployments of the Channel against the release of the applied pins
+// and values. Right after a pin change they still report the readiness of the previous release.
+func (r *ChannelReconciler) observeRelease(ctx context.Context, channel *multisuseiov1alpha1.Channel, source vendors.Source, pins versions.Pins, values helmvalues.Values) (releaseState, error) {
+	next, err := r.deploymentOptions(channel, source, pins, values)
+	if err != nil {
+		return releaseState{}, err
+	}
+	deployments, err := r.bundleDeploymentStates(ctx, channel, strings.ToLower(channel.Spec.Vendor))
+	if err != nil {
+		return releaseState{}, fmt.Errorf("failed to list BundleDeployments: %w", err)
+	}
+	// Clusters deploying a values override are only checked on the values it leaves alone
+	return verifyRelease(drift.ExpectedRelease(next.Helm).WithoutOverridden(values.Overrides), deployments), nil
+}
+
+// verifyRelease checks deployments against the expected release, the way the rollout health gate does
+func verifyRelease(expected drift.Expected, deployments []fleetutil.BundleDeploymentState) releaseState {
+	state := releaseState{Verified: len(deployments) > 0}
+	for _, bd := range deployments {
+		findings := drift.Detect(expected, nil, []fleetutil.BundleDeploymentState{bd})
+		if !bd.Ready || bd.Failed() || len(findings) > 0 {
+			state.Verified = false
+		}
+		if bd.Failed() && !hasFinding(findings, drift.TypeVersionMismatch) {
+			state.Failing = true
+		}
+	}
+	return state
+}
+
+func hasFinding(findings []drift.Finding, t drift.Type) bool {
+	for _, f := range findings {
+		if f.Type == t {
+			return true
+		}
+	}
+	return false
+}
+
+// trackRollout records the last known-good version and how long the rollout of the applied pins
+// has been failing. Only deployments verified on the applied release count. It returns true when
+// the failure crossed the Channel's rollback policy and the status was switched back to the last
+// known-good version.
+func (r *ChannelReconciler) trackRollout(ctx context.Context, channel *multisuseiov1alpha1.Channel, phase string, pins versions.Pins, release releaseState) bool {
+	status := &channel.Status
+	applied := toPinnedVersion(pins)
+
+	if phase == "Completed" && status.Rollout == nil && release.Verified {
+		status.LastKnownGood = &applied
+	}
+	if phase != "Failed" || !release.Failing {
+		status.FailingSince = nil
+		return false
+	}
+	if status.FailingSince == nil {
+		now := metav1.Now()
+		status.FailingSince = &now
+	}
+
+	policy := channel.Spec.RollbackOnFailure
+	if policy == nil || channel.Spec.RollbackTo != nil || status.LastKnownGood == nil || *status.LastKnownGood == applied {
+		return false
+	}
+	threshold := policy.FailureThreshold
+	if threshold < 1 {
+		threshold = 1
+	}
+	failingFor := time.Since(status.FailingSince.Time)
+	if status.FailedClusters < threshold || failingFor < policy.Timeout.Duration {
+		return false
+	}
+
+	good := *status.LastKnownGood
+	reason := fmt.Sprintf("%d cluster(s) failed on %s for %s, rolled back to %s",
+		status.FailedClusters, formatPins(applied), failingFor.Round(time.Second), formatPins(good))
+	log.FromContext(ctx).Info("Rolling back failed rollout", "channel", channel.Name, "reason", reason)
+
+	status.Rollback = &multisuseiov1alpha1.RollbackStatus{
+		From:   applied,
+		To:     good,
+		Time:   metav1.Now(),
+		Reason: reason,
+	}
+	status.Rollout = nil
+	status.CurrentPins = &good
+	status.FailingSince = nil
+	if n := len(status.History); n > 0 {
+		status.History[n-1].Outcome = multisuseiov1alpha1.RevisionRolledBack
+	}
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:    rolledBackConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "RollbackOnFailure",
+		Message: reason,
+	})
+	if r.Recorder != nil {
+		r.Recorder.Event(channel, corev1.EventTypeWarning, "RolledBack", reason)
+	}
+	return true
+}
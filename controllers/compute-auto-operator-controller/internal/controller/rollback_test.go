@@ -0,0 +1,167 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	multisuseiov1alpha1 "github.com/suse/rancher-multi-compute/api/multi.suse.io/v1alpha1"
+	"github.com/suse/rancher-multi-compute/internal/drift"
+	"github.com/suse/rancher-multi-compute/internal/fleetutil"
+	"github.com/suse/rancher-multi-compute/internal/versions"
+)
+
+var (
+	goodPins = versions.Pins{OperatorTag: "v24.6.0", RuntimeTag: "12.4.0", ChartVersion: "v24.6.0"}
+	newPins  = versions.Pins{OperatorTag: "v24.9.0", RuntimeTag: "12.4.1", ChartVersion: "v24.9.0"}
+)
+
+// releaseOf returns the release a Channel deploys for pins, as far as the tests check it
+func releaseOf(pins versions.Pins) drift.Expected {
+	return drift.Expected{Chart: "gpu-operator", Version: pins.ChartVersion}
+}
+
+// deployment returns the state of a BundleDeployment of cluster deploying pins
+func deployment(cluster string, pins versions.Pins, ready bool, state string) fleetutil.BundleDeploymentState {
+	return fleetutil.BundleDeploymentState{
+		Cluster: cluster,
+		Ready:   ready,
+		State:   state,
+		Helm:    fleetutil.HelmOptions{Chart: "gpu-operator", Version: pins.ChartVersion},
+	}
+}
+
+func rollbackChannel() *multisuseiov1alpha1.Channel {
+	good := toPinnedVersion(goodPins)
+	return &multisuseiov1alpha1.Channel{
+		ObjectMeta: metav1.ObjectMeta{Name: "nvidia-stable"},
+		Spec: multisuseiov1alpha1.ChannelSpec{
+			RollbackOnFailure: &multisuseiov1alpha1.RollbackPolicy{FailureThreshold: 1},
+		},
+		Status: multisuseiov1alpha1.ChannelStatus{
+			LastKnownGood: &good,
+			History: []multisuseiov1alpha1.ChannelRevision{
+				{Revision: 1, Version: good, Outcome: multisuseiov1alpha1.RevisionCompleted},
+				{Revision: 2, Version: toPinnedVersion(newPins), Outcome: multisuseiov1alpha1.RevisionRollingOut},
+			},
+		},
+	}
+}
+
+func TestEffectivePins(t *testing.T) {
+	r := &ChannelReconciler{}
+	ctx := context.Background()
+
+	channel := rollbackChannel()
+	assert.Equal(t, newPins, r.effectivePins(ctx, channel, newPins))
+
+	// The rolled back pins are deployed while the failed ones stay resolved
+	channel.Status.Rollback = &multisuseiov1alpha1.RollbackStatus{From: toPinnedVersion(newPins), To: toPinnedVersion(goodPins)}
+	meta.SetStatusCondition(&channel.Status.Conditions, metav1.Condition{Type: rolledBackConditionType, Status: metav1.ConditionTrue, Reason: "RollbackOnFailure"})
+	assert.Equal(t, goodPins, r.effectivePins(ctx, channel, newPins))
+	assert.NotNil(t, channel.Status.Rollback)
+
+	// Pinning a new version ends the rollback
+	fixed := versions.Pins{OperatorTag: "v24.9.1", RuntimeTag: "12.4.1", ChartVersion: "v24.9.1"}
+	assert.Equal(t, fixed, r.effectivePins(ctx, channel, fixed))
+	assert.Nil(t, channel.Status.Rollback)
+	assert.Nil(t, meta.FindStatusCondition(channel.Status.Conditions, rolledBackConditionType))
+}
+
+func TestVerifyRelease(t *testing.T) {
+	expected := releaseOf(newPins)
+
+	assert.Equal(t, releaseState{}, verifyRelease(expected, nil))
+	assert.Equal(t, releaseState{Verified: true}, verifyRelease(expected, []fleetutil.BundleDeploymentState{
+		deployment("a", newPins, true, "Ready"),
+		deployment("b", newPins, true, "Ready"),
+	}))
+	// Deployments still on the previous release neither verify nor fail the new one
+	assert.Equal(t, releaseState{}, verifyRelease(expected, []fleetutil.BundleDeploymentState{
+		deployment("a", newPins, true, "Ready"),
+		deployment("b", goodPins, false, "ErrApplied"),
+	}))
+	assert.Equal(t, releaseState{Failing: true}, verifyRelease(expected, []fleetutil.BundleDeploymentState{
+		deployment("a", newPins, true, "Ready"),
+		deployment("b", newPins, false, "ErrApplied"),
+	}))
+}
+
+func TestTrackRollout_BumpWhileOldDeploymentsReady(t *testing.T) {
+	r := &ChannelReconciler{}
+	channel := rollbackChannel()
+
+	// Right after the bump, the BundleDeployments are still ready on the previous release
+	release := verifyRelease(releaseOf(newPins), []fleetutil.BundleDeploymentState{deployment("a", goodPins, true, "Ready")})
+	assert.False(t, r.trackRollout(context.Background(), channel, "Completed", newPins, release))
+	assert.Equal(t, toPinnedVersion(goodPins), *channel.Status.LastKnownGood)
+
+	// Promoted once Fleet applied the new release
+	release = verifyRelease(releaseOf(newPins), []fleetutil.BundleDeploymentState{deployment("a", newPins, true, "Ready")})
+	assert.False(t, r.trackRollout(context.Background(), channel, "Completed", newPins, release))
+	assert.Equal(t, toPinnedVersion(newPins), *channel.Status.LastKnownGood)
+}
+
+func TestTrackRollout_FailureAfterBump(t *testing.T) {
+	recorder := record.NewFakeRecorder(1)
+	r := &ChannelReconciler{Recorder: recorder}
+	channel := rollbackChannel()
+	channel.Status.FailedClusters = 1
+
+	// Failures of the previous release are not blamed on the new one
+	release := verifyRelease(releaseOf(newPins), []fleetutil.BundleDeploymentState{deployment("a", goodPins, false, "ErrApplied")})
+	assert.False(t, r.trackRollout(context.Background(), channel, "Failed", newPins, release))
+	assert.Nil(t, channel.Status.FailingSince)
+
+	release = verifyRelease(releaseOf(newPins), []fleetutil.BundleDeploymentState{deployment("a", newPins, false, "ErrApplied")})
+	require.True(t, r.trackRollout(context.Background(), channel, "Failed", newPins, release))
+
+	rb := channel.Status.Rollback
+	require.NotNil(t, rb)
+	assert.Equal(t, toPinnedVersion(newPins), rb.From)
+	assert.Equal(t, toPinnedVersion(goodPins), rb.To)
+	assert.Equal(t, toPinnedVersion(goodPins), *channel.Status.CurrentPins)
+	assert.Nil(t, channel.Status.FailingSince)
+	assert.Equal(t, multisuseiov1alpha1.RevisionRolledBack, channel.Status.History[1].Outcome)
+	assert.True(t, meta.IsStatusConditionTrue(channel.Status.Conditions, rolledBackConditionType))
+	assert.Len(t, recorder.Events, 1)
+}
+
+func TestTrackRollout_Thresholds(t *testing.T) {
+	r := &ChannelReconciler{}
+	release := releaseState{Failing: true}
+
+	channel := rollbackChannel()
+	channel.Spec.RollbackOnFailure.FailureThreshold = 2
+	channel.Status.FailedClusters = 1
+	assert.False(t, r.trackRollout(context.Background(), channel, "Failed", newPins, release))
+	assert.NotNil(t, channel.Status.FailingSince)
+
+	channel = rollbackChannel()
+	channel.Spec.RollbackOnFailure.Timeout = metav1.Duration{Duration: 10 * time.Minute}
+	channel.Status.FailedClusters = 1
+	assert.False(t, r.trackRollout(context.Background(), channel, "Failed", newPins, release))
+
+	// The last known-good version is never rolled back from
+	channel = rollbackChannel()
+	channel.Status.FailedClusters = 1
+	assert.False(t, r.trackRollout(context.Background(), channel, "Failed", goodPins, release))
+}
+
+func TestTrackRollout_RollbackToSuppressesAutomaticRollback(t *testing.T) {
+	r := &ChannelReconciler{}
+	channel := rollbackChannel()
+	revision := int64(2)
+	channel.Spec.RollbackTo = &revision
+	channel.Status.FailedClusters = 1
+
+	assert.False(t, r.trackRollout(context.Background(), channel, "Failed", newPins, releaseState{Failing: true}))
+	assert.Nil(t, channel.Status.Rollback)
+	assert.NotNil(t, channel.Status.FailingSince)
+}
@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	multisuseiov1alpha1 "github.com/suse/rancher-multi-compute/api/multi.suse.io/v1alpha1"
+	"github.com/suse/rancher-multi-compute/internal/drift"
+	"github.com/suse/rancher-multi-compute/internal/fleetutil"
+	"github.com/suse/rancher-multi-compute/internal/helmvalues"
+	"github.com/suse/rancher-multi-compute/internal/rollout"
+	"github.com/suse/rancher-multi-compute/internal/vendors"
+	"github.com/suse/rancher-multi-compute/internal/versions"
+)
+
+// planTargets returns the Fleet targets for the Channel. With a rollout strategy, a pin change is
+// rolled out wave by wave: clusters of reached waves get the new pins, the others keep the previous
+// ones, and the next wave only starts once every reached cluster is ready on the new pins.
+func (r *ChannelReconciler) planTargets(ctx context.Context, channel *multisuseiov1alpha1.Channel, source vendors.Source, pins versions.Pins, values helmvalues.Values) ([]fleetutil.Target, error) {
+	logger := log.FromContext(ctx)
+	status := &channel.Status
+	selector := channel.Spec.ClusterSelector
+	next, err := r.deploymentOptions(channel, source, pins, values)
+	if err != nil {
+		return nil, err
+	}
+	desired := toPinnedVersion(pins)
+
+	if channel.Spec.RolloutStrategy == nil {
+		status.Rollout = nil
+		status.CurrentPins = &desired
+		return fleetutil.ConvertLabelSelectorToTargets(selector, next), nil
+	}
+
+	switch {
+	case status.Rollout == nil && status.CurrentPins != nil && *status.CurrentPins == desired:
+		return fleetutil.ConvertLabelSelectorToTargets(selector, next), nil
+	case status.Rollout == nil:
+		logger.Info("Starting staged rollout", "channel", channel.Name, "to", formatPins(desired))
+		status.Rollout = &multisuseiov1alpha1.RolloutStatus{
+			From:      status.CurrentPins,
+			To:        desired,
+			StartTime: metav1.Now(),
+		}
+	case status.Rollout.To != desired:
+		// Pins changed mid-rollout: restart from the first wave, unreached clusters keep the original version
+		logger.Info("Restarting staged rollout", "channel", channel.Name, "to", formatPins(desired))
+		status.Rollout.To = desired
+		status.Rollout.CurrentWave = 0
+		status.Rollout.StartTime = metav1.Now()
+	}
+
+	clusters, err := r.fleetClusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	clusters = withoutClusters(clusters, status.ExcludedClusters)
+	waves, err := rollout.Plan(selector, channel.Spec.RolloutStrategy, clusters)
+	if err != nil {
+		return nil, err
+	}
+	deployments, err := r.bundleDeploymentStates(ctx, channel, strings.ToLower(channel.Spec.Vendor))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list BundleDeployments: %w", err)
+	}
+
+	// Health gate: advance past every wave whose clusters are ready on the new pins
+	progress := status.Rollout
+	// Clusters deploying a values override are only checked on the values it leaves alone
+	expected := drift.ExpectedRelease(next.Helm).WithoutOverridden(values.Overrides)
+	for int(progress.CurrentWave) < len(waves) && rollout.Healthy(waves, int(progress.CurrentWave), expected, deployments) {
+		progress.CurrentWave++
+		if int(progress.CurrentWave) < len(waves) {
+			logger.Info("Rollout wave healthy, advancing", "channel", channel.Name, "wave", waves[progress.CurrentWave].Name)
+		}
+	}
+	if int(progress.CurrentWave) >= len(waves) {
+		logger.Info("Staged rollout completed", "channel", channel.Name, "version", formatPins(desired))
+		status.Rollout = nil
+		status.CurrentPins = &desired
+		return fleetutil.ConvertLabelSelectorToTargets(selector, next), nil
+	}
+
+	progress.TotalWaves = int32(len(waves))
+	progress.WaveName = waves[progress.CurrentWave].Name
+	var previous *fleetutil.BundleDeploymentOptions
+	if progress.From != nil {
+		previous, err = r.deploymentOptions(channel, source, fromPinnedVersion(*progress.From), values)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return rollout.Targets(selector, waves, int(progress.CurrentWave), next, previous), nil
+}
+
+// deploymentOptions renders the Fleet deployment options installing the vendor chart with the given pins
+// and the channel values
+func (r *ChannelReconciler) deploymentOptions(channel *multisuseiov1alpha1.Channel, source vendors.Source, pins versions.Pins, values helmvalues.Values) (*fleetutil.BundleDeploymentOptions, error) {
+	helm, err := fleetutil.ChartOptions(source.Repo, source.Chart)
+	if err != nil {
+		return nil, fmt.Errorf("invalid chart source: %w", err)
+	}
+	if pins.ChartVersion != "" {
+		// A channel pin takes precedence over the tag of an OCI chart source
+		helm.Version = pins.ChartVersion
+	}
+	helm.ReleaseName = fmt.Sprintf("%s-%s", strings.ToLower(channel.Spec.Vendor), channel.Spec.Channel)
+	helm.Values, err = r.buildHelmValues(source, pins, values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render Helm values: %w", err)
+	}
+	return &fleetutil.BundleDeploymentOptions{
+		DefaultNamespace: source.Namespace,
+		Helm:             &helm,
+	}, nil
+}
+
+// fleetClusters lists the Fleet clusters with their labels
+func (r *ChannelReconciler) fleetClusters(ctx context.Context) ([]rollout.Cluster, error) {
+	clusterList := &unstructured.UnstructuredList{}
+	clusterList.SetGroupVersionKind(clusterGVK)
+	if err := r.List(ctx, clusterList); err != nil {
+		return nil, fmt.Errorf("failed to list Fleet clusters: %w", err)
+	}
+
+	clusters := make([]rollout.Cluster, 0, len(clusterList.Items))
+	for _, c := range clusterList.Items {
+		clusters = append(clusters, rollout.Cluster{Name: c.GetName(), Labels: c.GetLabels()})
+	}
+	return clusters, nil
+}
+
+func toPinnedVersion(pins versions.Pins) multisuseiov1alpha1.PinnedVersion {
+	return multisuseiov1alpha1.PinnedVersion{
+		OperatorTag:  pins.OperatorTag,
+		RuntimeTag:   pins.RuntimeTag,
+		ChartVersion: pins.ChartVersion,
+	}
+}
+
+func fromPinnedVersion(pinned multisuseiov1alpha1.PinnedVersion) versions.Pins {
+	return versions.Pins{
+		OperatorTag:  pinned.OperatorTag,
+		RuntimeTag:   pinned.RuntimeTag,
+		ChartVersion: pinned.ChartVersion,
+	}
+}
+
+func formatPins(pinned multisuseiov1alpha1.PinnedVersion) string {
+	if pinned.ChartVersion != "" {
+		return fmt.Sprintf("%s/%s (chart %s)", pinned.OperatorTag, pinned.RuntimeTag, pinned.ChartVersion)
+	}
+	return fmt.Sprintf("%s/%s", pinned.OperatorTag, pinned.RuntimeTag)
+}
+
+// withoutClusters returns the clusters not named in excluded
+func withoutClusters(clusters []rollout.Cluster, excluded []string) []rollout.Cluster {
+	if len(excluded) == 0 {
+		return clusters
+	}
+	skip := map[string]bool{}
+	for _, name := range excluded {
+		skip[name] = true
+	}
+	out := make([]rollout.Cluster, 0, len(clusters))
+	for _, c := range clusters {
+		if !skip[c.Name] {
+			out = append(out, c)
+		}
+	}
+	return out
+}
@@ -0,0 +1,243 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	multisuseiov1alpha1 "github.com/suse/rancher-multi-compute/api/multi.suse.io/v1alpha1"
+	"github.com/suse/rancher-multi-compute/internal/fleetutil"
+	"github.com/suse/rancher-multi-compute/internal/helmvalues"
+	"github.com/suse/rancher-multi-compute/internal/testutil"
+	"github.com/suse/rancher-multi-compute/internal/vendors"
+	"github.com/suse/rancher-multi-compute/internal/versions"
+)
+
+var (
+	rolloutSource = vendors.Source{
+		Repo:      "https://nvidia.github.io/helm-charts",
+		Chart:     "gpu-operator",
+		Namespace: "gpu-operator",
+	}
+	deployedPins = versions.Pins{OperatorTag: "v24.6.0", RuntimeTag: "12.4.0"}
+	upgradePins  = versions.Pins{OperatorTag: "v24.9.0", RuntimeTag: "12.4.1"}
+)
+
+// rolloutChannel returns a Channel rolling out to one cluster at a time
+func rolloutChannel() *multisuseiov1alpha1.Channel {
+	maxUnavailable := intstr.FromInt32(1)
+	return &multisuseiov1alpha1.Channel{
+		ObjectMeta: metav1.ObjectMeta{Name: "nvidia-stable"},
+		Spec: multisuseiov1alpha1.ChannelSpec{
+			Vendor:          "nvidia",
+			Channel:         "stable",
+			ClusterSelector: metav1.LabelSelector{MatchLabels: map[string]string{"gpu": "nvidia"}},
+			RolloutStrategy: &multisuseiov1alpha1.RolloutStrategy{MaxUnavailable: &maxUnavailable},
+		},
+	}
+}
+
+func rolloutCluster(name string) *unstructured.Unstructured {
+	cluster := &unstructured.Unstructured{}
+	cluster.SetGroupVersionKind(clusterGVK)
+	cluster.SetNamespace("fleet-default")
+	cluster.SetName(name)
+	cluster.SetLabels(map[string]string{"gpu": "nvidia"})
+	return cluster
+}
+
+// readyDeployment returns a ready BundleDeployment of the rollout Channel deploying pins on cluster
+func readyDeployment(cluster string, pins versions.Pins) *unstructured.Unstructured {
+	bd := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"options": map[string]interface{}{
+				"helm": map[string]interface{}{
+					"repo":   rolloutSource.Repo,
+					"chart":  rolloutSource.Chart,
+					"values": pins.HelmValues(),
+				},
+			},
+		},
+		"status": map[string]interface{}{
+			"ready":   true,
+			"display": map[string]interface{}{"state": "Ready"},
+		},
+	}}
+	bd.SetGroupVersionKind(bdGVK)
+	bd.SetNamespace("cluster-fleet-default-" + cluster)
+	bd.SetName("nvidia-stable")
+	bd.SetLabels(map[string]string{
+		vendorLabelKey:         "nvidia",
+		ownerLabelKey:          "nvidia-stable",
+		fleetutil.ClusterLabel: cluster,
+	})
+	return bd
+}
+
+// describeTargets renders each target as its cluster, or "selector", and the operator tag it deploys
+func describeTargets(targets []fleetutil.Target) []string {
+	described := make([]string, 0, len(targets))
+	for _, target := range targets {
+		name := target.ClusterName
+		if name == "" {
+			name = "selector"
+		}
+		tag, _, _ := unstructured.NestedString(target.BundleDeploymentOptions.Helm.Values, "image", "operatorTag")
+		described = append(described, name+"="+tag)
+	}
+	return described
+}
+
+func pinned(pins versions.Pins) *multisuseiov1alpha1.PinnedVersion {
+	p := toPinnedVersion(pins)
+	return &p
+}
+
+func TestPlanTargets(t *testing.T) {
+	fixedPins := versions.Pins{OperatorTag: "v24.9.1", RuntimeTag: "12.4.1"}
+	started := metav1.NewTime(time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name        string
+		status      multisuseiov1alpha1.ChannelStatus
+		deployments []client.Object
+		pins        versions.Pins
+		// wantRollout is nil when no rollout is in progress afterwards
+		wantRollout *multisuseiov1alpha1.RolloutStatus
+		// wantRestart is set when the rollout is expected to start over from now
+		wantRestart bool
+		wantCurrent *multisuseiov1alpha1.PinnedVersion
+		wantTargets []string
+	}{
+		{
+			name:        "up to date",
+			status:      multisuseiov1alpha1.ChannelStatus{CurrentPins: pinned(deployedPins)},
+			pins:        deployedPins,
+			wantCurrent: pinned(deployedPins),
+			wantTargets: []string{"selector=v24.6.0"},
+		},
+		{
+			name:        "start",
+			status:      multisuseiov1alpha1.ChannelStatus{CurrentPins: pinned(deployedPins)},
+			pins:        upgradePins,
+			wantRestart: true,
+			wantRollout: &multisuseiov1alpha1.RolloutStatus{
+				From: pinned(deployedPins), To: toPinnedVersion(upgradePins), CurrentWave: 0, TotalWaves: 2, WaveName: "batch-1",
+			},
+			wantCurrent: pinned(deployedPins),
+			wantTargets: []string{"gpu-a=v24.9.0", "selector=v24.6.0"},
+		},
+		{
+			name:        "first rollout has no previous version",
+			status:      multisuseiov1alpha1.ChannelStatus{},
+			pins:        upgradePins,
+			wantRestart: true,
+			wantRollout: &multisuseiov1alpha1.RolloutStatus{
+				To: toPinnedVersion(upgradePins), CurrentWave: 0, TotalWaves: 2, WaveName: "batch-1",
+			},
+			wantTargets: []string{"gpu-a=v24.9.0"},
+		},
+		{
+			name: "waits for an unhealthy wave",
+			status: multisuseiov1alpha1.ChannelStatus{
+				CurrentPins: pinned(deployedPins),
+				Rollout:     &multisuseiov1alpha1.RolloutStatus{From: pinned(deployedPins), To: toPinnedVersion(upgradePins), StartTime: started},
+			},
+			deployments: []client.Object{readyDeployment("gpu-a", deployedPins), readyDeployment("gpu-b", deployedPins)},
+			pins:        upgradePins,
+			wantRollout: &multisuseiov1alpha1.RolloutStatus{
+				From: pinned(deployedPins), To: toPinnedVersion(upgradePins), CurrentWave: 0, TotalWaves: 2, WaveName: "batch-1", StartTime: started,
+			},
+			wantCurrent: pinned(deployedPins),
+			wantTargets: []string{"gpu-a=v24.9.0", "selector=v24.6.0"},
+		},
+		{
+			name: "advances past a healthy wave",
+			status: multisuseiov1alpha1.ChannelStatus{
+				CurrentPins: pinned(deployedPins),
+				Rollout:     &multisuseiov1alpha1.RolloutStatus{From: pinned(deployedPins), To: toPinnedVersion(upgradePins), StartTime: started},
+			},
+			deployments: []client.Object{readyDeployment("gpu-a", upgradePins), readyDeployment("gpu-b", deployedPins)},
+			pins:        upgradePins,
+			wantRollout: &multisuseiov1alpha1.RolloutStatus{
+				From: pinned(deployedPins), To: toPinnedVersion(upgradePins), CurrentWave: 1, TotalWaves: 2, WaveName: "batch-2", StartTime: started,
+			},
+			wantCurrent: pinned(deployedPins),
+			wantTargets: []string{"gpu-a=v24.9.0", "gpu-b=v24.9.0", "selector=v24.6.0"},
+		},
+		{
+			name: "restarts when the pins change mid-rollout",
+			status: multisuseiov1alpha1.ChannelStatus{
+				CurrentPins: pinned(deployedPins),
+				Rollout:     &multisuseiov1alpha1.RolloutStatus{From: pinned(deployedPins), To: toPinnedVersion(upgradePins), CurrentWave: 1, StartTime: started},
+			},
+			deployments: []client.Object{readyDeployment("gpu-a", upgradePins), readyDeployment("gpu-b", deployedPins)},
+			pins:        fixedPins,
+			wantRestart: true,
+			wantRollout: &multisuseiov1alpha1.RolloutStatus{
+				From: pinned(deployedPins), To: toPinnedVersion(fixedPins), CurrentWave: 0, TotalWaves: 2, WaveName: "batch-1",
+			},
+			wantCurrent: pinned(deployedPins),
+			wantTargets: []string{"gpu-a=v24.9.1", "selector=v24.6.0"},
+		},
+		{
+			name: "completes once every wave is healthy",
+			status: multisuseiov1alpha1.ChannelStatus{
+				CurrentPins: pinned(deployedPins),
+				Rollout:     &multisuseiov1alpha1.RolloutStatus{From: pinned(deployedPins), To: toPinnedVersion(upgradePins), CurrentWave: 1, StartTime: started},
+			},
+			deployments: []client.Object{readyDeployment("gpu-a", upgradePins), readyDeployment("gpu-b", upgradePins)},
+			pins:        upgradePins,
+			wantCurrent: pinned(upgradePins),
+			wantTargets: []string{"selector=v24.9.0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects := append([]client.Object{rolloutCluster("gpu-a"), rolloutCluster("gpu-b")}, tt.deployments...)
+			c := testutil.NewFakeClient(t, objects...)
+			r := &ChannelReconciler{Client: c, Scheme: c.Scheme()}
+			channel := rolloutChannel()
+			channel.Status = tt.status
+
+			targets, err := r.planTargets(context.Background(), channel, rolloutSource, tt.pins, helmvalues.Values{})
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTargets, describeTargets(targets))
+			assert.Equal(t, tt.wantCurrent, channel.Status.CurrentPins)
+
+			rollout := channel.Status.Rollout
+			if tt.wantRollout == nil {
+				assert.Nil(t, rollout)
+				return
+			}
+			require.NotNil(t, rollout)
+			if tt.wantRestart {
+				assert.True(t, rollout.StartTime.After(started.Time))
+				rollout.StartTime = metav1.Time{}
+			}
+			assert.Equal(t, tt.wantRollout, rollout)
+		})
+	}
+}
+
+func TestPlanTargets_NoStrategy(t *testing.T) {
+	c := testutil.NewFakeClient(t)
+	r := &ChannelReconciler{Client: c, Scheme: c.Scheme()}
+	channel := rolloutChannel()
+	channel.Spec.RolloutStrategy = nil
+	channel.Status.Rollout = &multisuseiov1alpha1.RolloutStatus{From: pinned(deployedPins), To: toPinnedVersion(upgradePins)}
+
+	targets, err := r.planTargets(context.Background(), channel, rolloutSource, upgradePins, helmvalues.Values{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"selector=v24.9.0"}, describeTargets(targets))
+	assert.Nil(t, channel.Status.Rollout)
+	assert.Equal(t, pinned(upgradePins), channel.Status.CurrentPins)
+}
@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	multisuseiov1alpha1 "github.com/suse/rancher-multi-compute/api/multi.suse.io/v1alpha1"
+	"github.com/suse/rancher-multi-compute/internal/vendors"
+)
+
+const (
+	// DefaultConfigName is the MultiComputeConfig whose vendor sources override the registry
+	DefaultConfigName = "global-config"
+	// defaultSourceOrigin reports a vendor source taken from the controller's vendor registry
+	defaultSourceOrigin = "Default"
+)
+
+// resolveVendorSource returns the source of the Channel's vendor, the entry of the
+// MultiComputeConfig when it defines one and the registry entry otherwise
+func (r *ChannelReconciler) resolveVendorSource(ctx context.Context, channel *multisuseiov1alpha1.Channel) (vendors.Vendor, *multisuseiov1alpha1.ChannelVendorSource, error) {
+	vendor, source, registered := r.VendorSources.Lookup(channel.Spec.Vendor)
+
+	if r.ConfigName != "" {
+		config := &multisuseiov1alpha1.MultiComputeConfig{}
+		err := r.Get(ctx, types.NamespacedName{Name: r.ConfigName}, config)
+		if err != nil && !errors.IsNotFound(err) {
+			return vendor, nil, fmt.Errorf("failed to get MultiComputeConfig %s: %w", r.ConfigName, err)
+		}
+		for name, override := range config.Spec.VendorSources {
+			if strings.EqualFold(name, string(vendor)) {
+				if override.ValuesTemplate == "" {
+					// Keep mapping the pins to the chart of the vendor
+					override.ValuesTemplate = source.ValuesTemplate
+				}
+				return vendor, &multisuseiov1alpha1.ChannelVendorSource{
+					VendorSource: override,
+					Origin:       "MultiComputeConfig/" + config.Name,
+				}, nil
+			}
+		}
+	}
+
+	if !registered {
+		return vendor, nil, nil
+	}
+	return vendor, &multisuseiov1alpha1.ChannelVendorSource{
+		VendorSource: multisuseiov1alpha1.VendorSource{
+			Repo:                  source.Repo,
+			Chart:                 source.Chart,
+			Namespace:             source.Namespace,
+			HelmSecretName:        source.HelmSecretName,
+			CABundle:              source.CABundle,
+			InsecureSkipTLSVerify: source.InsecureSkipTLSVerify,
+			ValuesTemplate:        source.ValuesTemplate,
+		},
+		Origin: defaultSourceOrigin,
+	}, nil
+}
+
+// isVendorSourcesConfig reports whether obj is the MultiComputeConfig overriding the vendor sources
+func (r *ChannelReconciler) isVendorSourcesConfig(obj client.Object) bool {
+	return r.ConfigName != "" && obj.GetName() == r.ConfigName
+}
+
+// allChannels enqueues every Channel, as a MultiComputeConfig change may move any of them to another source
+func (r *ChannelReconciler) allChannels(ctx context.Context, obj client.Object) []reconcile.Request {
+	channels := &multisuseiov1alpha1.ChannelList{}
+	if err := r.List(ctx, channels); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list Channels for MultiComputeConfig", "config", obj.GetName())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(channels.Items))
+	for _, channel := range channels.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&channel)})
+	}
+	return requests
+}
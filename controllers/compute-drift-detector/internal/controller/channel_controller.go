@@ -2,28 +2,80 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	multisuseiov1alpha1 "github.com/suse/rancher-multi-compute/api/multi.suse.io/v1alpha1"
+	"github.com/suse/rancher-multi-compute/internal/drift"
+	"github.com/suse/rancher-multi-compute/internal/fleetutil"
+	"github.com/suse/rancher-multi-compute/internal/helmvalues"
+	"github.com/suse/rancher-multi-compute/internal/vendors"
+	"github.com/suse/rancher-multi-compute/internal/versions"
+)
+
+// GVKs for Fleet
+var (
+	bundleGVK = schema.GroupVersionKind{
+		Group:   "fleet.cattle.io",
+		Version: "v1alpha1",
+		Kind:    "Bundle",
+	}
+	bdGVK = schema.GroupVersionKind{
+		Group:   "fleet.cattle.io",
+		Version: "v1alpha1",
+		Kind:    "BundleDeployment",
+	}
+	clusterGVK = schema.GroupVersionKind{
+		Group:   "fleet.cattle.io",
+		Version: "v1alpha1",
+		Kind:    "Cluster",
+	}
+)
+
+const (
+	fleetSystemNamespace = "cattle-fleet-system"
+	bundleNamePrefix     = "rmc-"
+	ownerLabelKey        = "multi.suse.io/owner"
+	driftConditionType   = "DriftDetected"
+	// maxReportedDrift bounds the per-cluster drift entries kept in status
+	maxReportedDrift = 50
+	// remediationCooldown gives Fleet time to redeploy before drift is remediated again
+	remediationCooldown = 10 * time.Minute
 )
 
 // ChannelReconciler reconciles a Channel object for drift detection
 type ChannelReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme          *runtime.Scheme
+	Recorder        record.EventRecorder
+	VersionResolver versions.Resolver
+	VendorSources   vendors.Registry
 }
 
 //+kubebuilder:rbac:groups=multi.suse.io,resources=channels,verbs=get;list;watch
 //+kubebuilder:rbac:groups=multi.suse.io,resources=channels/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=fleet.cattle.io,resources=bundles,verbs=get;list;watch;update;patch
 //+kubebuilder:rbac:groups=fleet.cattle.io,resources=bundledeployments,verbs=get;list;watch
+//+kubebuilder:rbac:groups=fleet.cattle.io,resources=clusters,verbs=get;list;watch
 //+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop
 func (r *ChannelReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -37,48 +89,377 @@ func (r *ChannelReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		}
 		return ctrl.Result{}, err
 	}
+	if !channel.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
 
-	// Detect drift between declared Channel and actual deployments
-	driftDetected, driftDetails := r.detectDrift(ctx, channel)
+	original := channel.Status.DeepCopy()
 
-	if driftDetected {
-		logger.Info("Drift detected", "channel", channel.Name, "details", driftDetails)
+	if channel.Spec.DriftPolicy == multisuseiov1alpha1.DriftPolicyIgnore {
+		logger.Info("Drift detection disabled", "channel", channel.Name)
+		meta.RemoveStatusCondition(&channel.Status.Conditions, driftConditionType)
+		channel.Status.Drift = nil
+		return ctrl.Result{}, r.updateStatusIfChanged(ctx, channel, original)
+	}
 
-		// Update Channel status with drift information
-		channel.Status.Phase = "DriftDetected"
+	if channel.Status.Rollout != nil {
+		// Clusters of waves not reached yet intentionally run the previous version
+		meta.SetStatusCondition(&channel.Status.Conditions, metav1.Condition{
+			Type:    driftConditionType,
+			Status:  metav1.ConditionUnknown,
+			Reason:  "RolloutInProgress",
+			Message: "drift detection resumes once the staged rollout completes",
+		})
+		channel.Status.Drift = nil
+		return ctrl.Result{RequeueAfter: 10 * time.Minute}, r.updateStatusIfChanged(ctx, channel, original)
+	}
 
-		// Add drift condition
-		condition := metav1.Condition{
-			Type:               "DriftDetected",
-			Status:             metav1.ConditionTrue,
-			Reason:             "ConfigurationDrift",
-			Message:            driftDetails,
-			LastTransitionTime: metav1.Now(),
+	// Detect drift between declared Channel and actual deployments
+	report, err := r.detectDrift(ctx, channel)
+	if err != nil {
+		logger.Error(err, "failed to detect drift", "channel", channel.Name)
+		meta.SetStatusCondition(&channel.Status.Conditions, metav1.Condition{
+			Type:    driftConditionType,
+			Status:  metav1.ConditionUnknown,
+			Reason:  "DriftCheckFailed",
+			Message: err.Error(),
+		})
+		if updateErr := r.updateStatusIfChanged(ctx, channel, original); updateErr != nil {
+			logger.Error(updateErr, "failed to update Channel status")
 		}
-		channel.Status.Conditions = append(channel.Status.Conditions, condition)
+		return ctrl.Result{}, err
+	}
 
-		if err := r.Status().Update(ctx, channel); err != nil {
-			logger.Error(err, "failed to update Channel status with drift")
-			return ctrl.Result{}, err
-		}
+	condition := metav1.Condition{
+		Type:    driftConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  "NoDrift",
+		Message: drift.Summarize(report.findings, report.targeted),
+	}
+	if len(report.findings) > 0 {
+		logger.Info("Drift detected", "channel", channel.Name, "details", condition.Message)
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "ConfigurationDrift"
 	} else {
 		logger.Info("No drift detected", "channel", channel.Name)
 	}
+	meta.SetStatusCondition(&channel.Status.Conditions, condition)
+	channel.Status.Drift = toClusterDrift(report.findings)
+
+	if channel.Spec.DriftPolicy == multisuseiov1alpha1.DriftPolicyRemediate && len(report.findings) > 0 {
+		if channel.Spec.Paused {
+			logger.Info("Skipping remediation of paused Channel", "channel", channel.Name)
+		} else if r.remediationDue(channel) {
+			if err := r.remediate(ctx, channel, report); err != nil {
+				logger.Error(err, "failed to remediate drift", "channel", channel.Name)
+				// Report the drift even though it could not be corrected
+				if updateErr := r.updateStatusIfChanged(ctx, channel, original); updateErr != nil {
+					logger.Error(updateErr, "failed to update Channel status with drift")
+				}
+				return ctrl.Result{}, err
+			}
+		} else {
+			logger.Info("Skipping remediation during cooldown", "channel", channel.Name)
+		}
+	}
+
+	if err := r.updateStatusIfChanged(ctx, channel, original); err != nil {
+		logger.Error(err, "failed to update Channel status with drift")
+		return ctrl.Result{}, err
+	}
 
 	return ctrl.Result{RequeueAfter: 10 * time.Minute}, nil
 }
 
-// detectDrift checks for configuration drift
-func (r *ChannelReconciler) detectDrift(ctx context.Context, channel *multisuseiov1alpha1.Channel) (bool, string) {
-	// This is a simplified drift detection implementation
-	// In a real implementation, you would:
-	// 1. Compare Channel spec with actual BundleDeployment status
-	// 2. Check for version mismatches
-	// 3. Verify resource configurations
-	// 4. Check for policy violations
-
-	// For now, return no drift
-	return false, ""
+// driftReport is the outcome of comparing a Channel against its deployments
+type driftReport struct {
+	findings    []drift.Finding
+	deployments []fleetutil.BundleDeploymentState
+	targeted    int
+	// clusters holds the labels of every Fleet cluster, which Bundle targets are matched against
+	clusters map[string]labels.Set
+}
+
+// detectDrift compares the Channel's BundleDeployments against the pins resolved for its release channel
+func (r *ChannelReconciler) detectDrift(ctx context.Context, channel *multisuseiov1alpha1.Channel) (driftReport, error) {
+	vendorPins, err := r.VersionResolver.Resolve(ctx, channel.Spec.Channel)
+	if err != nil {
+		return driftReport{}, fmt.Errorf("failed to resolve versions for channel %s: %w", channel.Spec.Channel, err)
+	}
+	pins, ok := vendorPins.ForVendor(channel.Spec.Vendor)
+	if !ok {
+		return driftReport{}, fmt.Errorf("no version pins for vendor %s in channel %s", channel.Spec.Vendor, channel.Spec.Channel)
+	}
+	rb := channel.Status.Rollback
+	rolledBack := rb != nil && rb.From.OperatorTag == pins.OperatorTag && rb.From.RuntimeTag == pins.RuntimeTag &&
+		rb.From.ChartVersion == pins.ChartVersion
+	if current := channel.Status.CurrentPins; current != nil && (rolledBack || channel.Spec.RollbackTo != nil || channel.Spec.Paused) {
+		// The auto-operator rolled the Channel back to a previous version, or holds a paused Channel
+		// on the version it last deployed
+		pins = versions.Pins{OperatorTag: current.OperatorTag, RuntimeTag: current.RuntimeTag, ChartVersion: current.ChartVersion}
+	}
+	_, source, ok := r.VendorSources.Lookup(channel.Spec.Vendor)
+	if reported := channel.Status.VendorSource; reported != nil {
+		// The auto-operator may deploy from a source overridden by the MultiComputeConfig
+		source, ok = vendors.Source{
+			Repo:           reported.Repo,
+			Chart:          reported.Chart,
+			Namespace:      reported.Namespace,
+			ValuesTemplate: reported.ValuesTemplate,
+		}, true
+	}
+	if !ok {
+		return driftReport{}, fmt.Errorf("no source configuration found for vendor: %s", channel.Spec.Vendor)
+	}
+
+	bundles, err := r.ownedBundles(ctx, channel)
+	if err != nil {
+		return driftReport{}, err
+	}
+	deployments, err := r.bundleDeployments(ctx, channel, bundles)
+	if err != nil {
+		return driftReport{}, err
+	}
+	fleetClusters, err := r.fleetClusters(ctx)
+	if err != nil {
+		return driftReport{}, err
+	}
+	clusters, err := targetedClusters(channel, fleetClusters)
+	if err != nil {
+		return driftReport{}, err
+	}
+
+	chart, err := fleetutil.ChartOptions(source.Repo, source.Chart)
+	if err != nil {
+		return driftReport{}, fmt.Errorf("invalid chart source for vendor %s: %w", channel.Spec.Vendor, err)
+	}
+	if pins.ChartVersion != "" {
+		chart.Version = pins.ChartVersion
+	}
+	pinValues, err := source.HelmValues(pins)
+	if err != nil {
+		return driftReport{}, fmt.Errorf("failed to render Helm values for vendor %s: %w", channel.Spec.Vendor, err)
+	}
+	channelValues, err := helmvalues.Load(ctx, r, fleetSystemNamespace, &channel.Spec)
+	if err != nil {
+		return driftReport{}, err
+	}
+	chart.Values = channelValues.Apply(pinValues)
+	// Clusters deploying a values override are only checked on the values it leaves alone
+	expected := drift.ExpectedRelease(&chart).WithoutOverridden(channelValues.Overrides)
+	return driftReport{
+		findings:    drift.Detect(expected, clusters, deployments),
+		deployments: deployments,
+		targeted:    len(clusters),
+		clusters:    fleetClusters,
+	}, nil
+}
+
+// remediationDue reports whether enough time passed since the last remediation for Fleet to settle
+func (r *ChannelReconciler) remediationDue(channel *multisuseiov1alpha1.Channel) bool {
+	last := channel.Status.LastRemediation
+	return last == nil || time.Since(last.Time.Time) >= remediationCooldown
+}
+
+// correctable reports whether a redeploy corrects a finding. Fleet restores modified and missing
+// resources, while a version mismatch or a missing deployment comes from the Bundle itself.
+func correctable(f drift.Finding) bool {
+	return f.Type == drift.TypeModifiedResources
+}
+
+// remediate force-syncs the BundleDeployments of every drifted cluster and records what was corrected
+func (r *ChannelReconciler) remediate(ctx context.Context, channel *multisuseiov1alpha1.Channel, report driftReport) error {
+	logger := log.FromContext(ctx)
+
+	byCluster := map[string]fleetutil.BundleDeploymentState{}
+	for _, bd := range report.deployments {
+		byCluster[bd.Cluster] = bd
+	}
+
+	remediation := &multisuseiov1alpha1.DriftRemediation{Time: metav1.Now()}
+	skipped := map[string]bool{}
+	synced := map[string]bool{}
+	bundles := map[string][]string{}
+	var corrected []drift.Finding
+	for _, f := range report.findings {
+		bd, ok := byCluster[f.Cluster]
+		if !ok || bd.Bundle == "" || !correctable(f) {
+			if !skipped[f.Cluster] {
+				skipped[f.Cluster] = true
+				remediation.Skipped = append(remediation.Skipped, f.Cluster)
+			}
+			continue
+		}
+		if !synced[f.Cluster] {
+			synced[f.Cluster] = true
+			bundles[bd.Bundle] = append(bundles[bd.Bundle], f.Cluster)
+		}
+		corrected = append(corrected, f)
+	}
+
+	names := make([]string, 0, len(bundles))
+	for name := range bundles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var clusters []string
+	for _, name := range names {
+		if err := r.forceSync(ctx, name, bundles[name], report.clusters); err != nil {
+			return err
+		}
+		logger.Info("Forced redeploy of drifted clusters", "bundle", name, "clusters", bundles[name])
+		clusters = append(clusters, bundles[name]...)
+	}
+	remediation.Corrected = toClusterDrift(corrected)
+	channel.Status.LastRemediation = remediation
+
+	if r.Recorder != nil && len(clusters) > 0 {
+		sort.Strings(clusters)
+		r.Recorder.Eventf(channel, corev1.EventTypeNormal, "DriftRemediated",
+			"Forced redeploy on %d cluster(s): %s", len(clusters), strings.Join(clusters, ", "))
+	}
+	return nil
+}
+
+// forceSync bumps the force-sync generation of the Bundle targets deploying the given clusters so the
+// Fleet agents redeploy them. Fleet renders the BundleDeployments from the Bundle, so the generation
+// is set on the target the cluster matches rather than on its BundleDeployment.
+func (r *ChannelReconciler) forceSync(ctx context.Context, name string, clusters []string, clusterLabels map[string]labels.Set) error {
+	bundle := &unstructured.Unstructured{}
+	bundle.SetGroupVersionKind(bundleGVK)
+	if err := r.Get(ctx, client.ObjectKey{Namespace: fleetSystemNamespace, Name: name}, bundle); err != nil {
+		return fmt.Errorf("failed to get Bundle %s: %w", name, err)
+	}
+	targets, err := fleetutil.BundleTargets(bundle)
+	if err != nil {
+		return err
+	}
+
+	bumped := map[int]bool{}
+	for _, cluster := range clusters {
+		index, ok, err := fleetutil.MatchTarget(targets, cluster, clusterLabels[cluster])
+		if err != nil {
+			return fmt.Errorf("failed to match cluster %s against Bundle %s: %w", cluster, name, err)
+		}
+		if !ok {
+			return fmt.Errorf("no target of Bundle %s deploys cluster %s", name, cluster)
+		}
+		if bumped[index] {
+			continue
+		}
+		if _, err := fleetutil.BumpForceSyncGeneration(bundle, index); err != nil {
+			return err
+		}
+		bumped[index] = true
+	}
+	if err := r.Update(ctx, bundle); err != nil {
+		return fmt.Errorf("failed to force-sync Bundle %s: %w", name, err)
+	}
+	return nil
+}
+
+func (r *ChannelReconciler) updateStatusIfChanged(ctx context.Context, channel *multisuseiov1alpha1.Channel, original *multisuseiov1alpha1.ChannelStatus) error {
+	if equality.Semantic.DeepEqual(original, &channel.Status) {
+		return nil
+	}
+	return r.Status().Update(ctx, channel)
+}
+
+// ownedBundles returns the names of the rmc-* Bundles owned by the Channel
+func (r *ChannelReconciler) ownedBundles(ctx context.Context, channel *multisuseiov1alpha1.Channel) (map[string]bool, error) {
+	bundleList := &unstructured.UnstructuredList{}
+	bundleList.SetGroupVersionKind(bundleGVK)
+	if err := r.List(ctx, bundleList,
+		client.InNamespace(fleetSystemNamespace),
+		client.MatchingLabels{ownerLabelKey: channel.Name},
+	); err != nil {
+		return nil, fmt.Errorf("failed to list Bundles: %w", err)
+	}
+
+	names := map[string]bool{}
+	for _, b := range bundleList.Items {
+		if strings.HasPrefix(b.GetName(), bundleNamePrefix) {
+			names[b.GetName()] = true
+		}
+	}
+	return names, nil
+}
+
+// bundleDeployments returns the BundleDeployments created by Fleet for the given Bundles
+func (r *ChannelReconciler) bundleDeployments(ctx context.Context, channel *multisuseiov1alpha1.Channel, bundles map[string]bool) ([]fleetutil.BundleDeploymentState, error) {
+	if len(bundles) == 0 {
+		return nil, nil
+	}
+
+	bds := &unstructured.UnstructuredList{}
+	bds.SetGroupVersionKind(bdGVK)
+	if err := r.List(ctx, bds, client.MatchingLabels{ownerLabelKey: channel.Name}); err != nil {
+		return nil, fmt.Errorf("failed to list BundleDeployments: %w", err)
+	}
+
+	var states []fleetutil.BundleDeploymentState
+	for i := range bds.Items {
+		state := fleetutil.ParseBundleDeployment(&bds.Items[i])
+		if state.Bundle != "" && !bundles[state.Bundle] {
+			continue
+		}
+		states = append(states, state)
+	}
+	return states, nil
+}
+
+// fleetClusters returns the labels of every Fleet cluster by name
+func (r *ChannelReconciler) fleetClusters(ctx context.Context) (map[string]labels.Set, error) {
+	clusterList := &unstructured.UnstructuredList{}
+	clusterList.SetGroupVersionKind(clusterGVK)
+	if err := r.List(ctx, clusterList); err != nil {
+		return nil, fmt.Errorf("failed to list Fleet clusters: %w", err)
+	}
+
+	clusters := make(map[string]labels.Set, len(clusterList.Items))
+	for _, c := range clusterList.Items {
+		clusters[c.GetName()] = labels.Set(c.GetLabels())
+	}
+	return clusters, nil
+}
+
+// targetedClusters returns the names of the Fleet clusters matched by the Channel's cluster selector
+// and not excluded in favor of a higher-priority Channel
+func targetedClusters(channel *multisuseiov1alpha1.Channel, clusters map[string]labels.Set) ([]string, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&channel.Spec.ClusterSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cluster selector: %w", err)
+	}
+
+	// Clusters left to a higher-priority Channel are not expected to run this one
+	excluded := map[string]bool{}
+	for _, name := range channel.Status.ExcludedClusters {
+		excluded[name] = true
+	}
+
+	var names []string
+	for name, clusterLabels := range clusters {
+		if selector.Matches(clusterLabels) && !excluded[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func toClusterDrift(findings []drift.Finding) []multisuseiov1alpha1.ClusterDrift {
+	if len(findings) > maxReportedDrift {
+		findings = findings[:maxReportedDrift]
+	}
+	var out []multisuseiov1alpha1.ClusterDrift
+	for _, f := range findings {
+		out = append(out, multisuseiov1alpha1.ClusterDrift{
+			Cluster: f.Cluster,
+			Type:    string(f.Type),
+			Message: f.Message,
+		})
+	}
+	return out
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -0,0 +1,309 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	multisuseiov1alpha1 "github.com/suse/rancher-multi-compute/api/multi.suse.io/v1alpha1"
+	"github.com/suse/rancher-multi-compute/internal/drift"
+	"github.com/suse/rancher-multi-compute/internal/fleetutil"
+	"github.com/suse/rancher-multi-compute/internal/testutil"
+	"github.com/suse/rancher-multi-compute/internal/vendors"
+	"github.com/suse/rancher-multi-compute/internal/versions"
+)
+
+const testBundle = "rmc-nvidia-stable"
+
+var testPins = versions.Pins{OperatorTag: "v24.9.0", RuntimeTag: "12.4.1", ChartVersion: "v24.9.0"}
+
+// staticResolver resolves every release channel to the same pins
+type staticResolver versions.VendorPins
+
+func (s staticResolver) Resolve(context.Context, string) (versions.VendorPins, error) {
+	return versions.VendorPins(s), nil
+}
+
+func newReconciler(t *testing.T, objects ...client.Object) (*ChannelReconciler, *record.FakeRecorder) {
+	recorder := record.NewFakeRecorder(10)
+	return &ChannelReconciler{
+		Client:          testutil.NewFakeClient(t, objects...),
+		Recorder:        recorder,
+		VersionResolver: staticResolver{"nvidia": testPins},
+		VendorSources: vendors.Registry{vendors.VendorNVIDIA: {
+			Repo:      "https://nvidia.github.io/helm-charts",
+			Chart:     "gpu-operator",
+			Namespace: "gpu-operator",
+		}},
+	}, recorder
+}
+
+func testChannel(policy string) *multisuseiov1alpha1.Channel {
+	return &multisuseiov1alpha1.Channel{
+		ObjectMeta: metav1.ObjectMeta{Name: "nvidia-stable"},
+		Spec: multisuseiov1alpha1.ChannelSpec{
+			Vendor:          "nvidia",
+			Channel:         "stable",
+			ClusterSelector: metav1.LabelSelector{MatchLabels: map[string]string{"gpu": "nvidia"}},
+			DriftPolicy:     policy,
+		},
+	}
+}
+
+// testBundleObject returns the Bundle of the test Channel, canary clusters targeted first
+func testBundleObject(t *testing.T) *unstructured.Unstructured {
+	options := &fleetutil.BundleDeploymentOptions{Helm: &fleetutil.HelmOptions{Chart: "gpu-operator"}}
+	targets, err := fleetutil.TargetsToUnstructured([]fleetutil.Target{
+		{ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"stage": "canary"}}, BundleDeploymentOptions: options},
+		{ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"gpu": "nvidia"}}, BundleDeploymentOptions: options},
+	})
+	require.NoError(t, err)
+
+	bundle := &unstructured.Unstructured{}
+	bundle.SetGroupVersionKind(bundleGVK)
+	bundle.SetNamespace(fleetSystemNamespace)
+	bundle.SetName(testBundle)
+	bundle.SetLabels(map[string]string{ownerLabelKey: "nvidia-stable"})
+	require.NoError(t, unstructured.SetNestedSlice(bundle.Object, targets, "spec", "targets"))
+	return bundle
+}
+
+func fleetCluster(name string, clusterLabels map[string]string) *unstructured.Unstructured {
+	cluster := &unstructured.Unstructured{}
+	cluster.SetGroupVersionKind(clusterGVK)
+	cluster.SetNamespace("fleet-default")
+	cluster.SetName(name)
+	cluster.SetLabels(clusterLabels)
+	return cluster
+}
+
+// modifiedDeployment returns a BundleDeployment of the test Bundle whose resources were modified
+func modifiedDeployment(cluster string) *unstructured.Unstructured {
+	bd := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"options": map[string]interface{}{
+				"helm": map[string]interface{}{
+					"repo":    "https://nvidia.github.io/helm-charts",
+					"chart":   "gpu-operator",
+					"version": testPins.ChartVersion,
+					"values":  testPins.HelmValues(),
+				},
+			},
+		},
+		"status": map[string]interface{}{
+			"display": map[string]interface{}{"state": "Modified"},
+		},
+	}}
+	bd.SetGroupVersionKind(bdGVK)
+	bd.SetNamespace("cluster-fleet-default-" + cluster)
+	bd.SetName(testBundle)
+	bd.SetLabels(map[string]string{
+		ownerLabelKey:             "nvidia-stable",
+		fleetutil.BundleNameLabel: testBundle,
+		fleetutil.ClusterLabel:    cluster,
+	})
+	return bd
+}
+
+// targetGenerations returns the forceSyncGeneration of every target of the test Bundle
+func targetGenerations(t *testing.T, c client.Client) []int64 {
+	bundle := &unstructured.Unstructured{}
+	bundle.SetGroupVersionKind(bundleGVK)
+	require.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: fleetSystemNamespace, Name: testBundle}, bundle))
+	targets, err := fleetutil.BundleTargets(bundle)
+	require.NoError(t, err)
+	generations := make([]int64, 0, len(targets))
+	for _, target := range targets {
+		generations = append(generations, target.ForceSyncGeneration)
+	}
+	return generations
+}
+
+func TestRemediate(t *testing.T) {
+	r, recorder := newReconciler(t, testBundleObject(t))
+	channel := testChannel(multisuseiov1alpha1.DriftPolicyRemediate)
+
+	state := func(cluster string) fleetutil.BundleDeploymentState {
+		return fleetutil.BundleDeploymentState{Name: testBundle, Bundle: testBundle, Cluster: cluster}
+	}
+	report := driftReport{
+		findings: []drift.Finding{
+			{Cluster: "gpu-a", Type: drift.TypeModifiedResources, Message: "DaemonSet gpu-operator/nvidia-driver (modified)"},
+			{Cluster: "gpu-b", Type: drift.TypeModifiedResources, Message: "ConfigMap gpu-operator/toolkit (missing)"},
+			{Cluster: "gpu-c", Type: drift.TypeVersionMismatch, Message: `version: expected "v24.9.0", deployed "v24.6.0"`},
+			{Cluster: "gpu-d", Type: drift.TypeMissingDeployment},
+		},
+		deployments: []fleetutil.BundleDeploymentState{state("gpu-a"), state("gpu-b"), state("gpu-c")},
+		clusters: map[string]labels.Set{
+			"gpu-a": {"gpu": "nvidia"},
+			"gpu-b": {"gpu": "nvidia"},
+			"gpu-c": {"gpu": "nvidia", "stage": "canary"},
+			"gpu-d": {"gpu": "nvidia"},
+		},
+	}
+
+	require.NoError(t, r.remediate(context.Background(), channel, report))
+
+	// The target deploying both modified clusters is force-synced once, the canary target is left alone
+	assert.Equal(t, []int64{0, 1}, targetGenerations(t, r.Client))
+
+	remediation := channel.Status.LastRemediation
+	require.NotNil(t, remediation)
+	assert.Equal(t, []multisuseiov1alpha1.ClusterDrift{
+		{Cluster: "gpu-a", Type: string(drift.TypeModifiedResources), Message: "DaemonSet gpu-operator/nvidia-driver (modified)"},
+		{Cluster: "gpu-b", Type: string(drift.TypeModifiedResources), Message: "ConfigMap gpu-operator/toolkit (missing)"},
+	}, remediation.Corrected)
+	// A redeploy restores neither another version nor a missing deployment
+	assert.Equal(t, []string{"gpu-c", "gpu-d"}, remediation.Skipped)
+
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "Forced redeploy on 2 cluster(s): gpu-a, gpu-b")
+}
+
+func TestRemediate_VersionMismatchIsNotCorrected(t *testing.T) {
+	r, recorder := newReconciler(t, testBundleObject(t))
+	channel := testChannel(multisuseiov1alpha1.DriftPolicyRemediate)
+
+	report := driftReport{
+		findings: []drift.Finding{{Cluster: "gpu-a", Type: drift.TypeVersionMismatch}},
+		deployments: []fleetutil.BundleDeploymentState{
+			{Name: testBundle, Bundle: testBundle, Cluster: "gpu-a"},
+		},
+		clusters: map[string]labels.Set{"gpu-a": {"gpu": "nvidia"}},
+	}
+	require.NoError(t, r.remediate(context.Background(), channel, report))
+
+	assert.Equal(t, []int64{0, 0}, targetGenerations(t, r.Client))
+	assert.Empty(t, channel.Status.LastRemediation.Corrected)
+	assert.Equal(t, []string{"gpu-a"}, channel.Status.LastRemediation.Skipped)
+	assert.Empty(t, recorder.Events)
+}
+
+func TestRemediationDue(t *testing.T) {
+	r := &ChannelReconciler{}
+	channel := testChannel(multisuseiov1alpha1.DriftPolicyRemediate)
+	assert.True(t, r.remediationDue(channel))
+
+	channel.Status.LastRemediation = &multisuseiov1alpha1.DriftRemediation{Time: metav1.NewTime(time.Now().Add(-time.Minute))}
+	assert.False(t, r.remediationDue(channel))
+
+	channel.Status.LastRemediation.Time = metav1.NewTime(time.Now().Add(-remediationCooldown))
+	assert.True(t, r.remediationDue(channel))
+}
+
+func TestReconcile_Remediate(t *testing.T) {
+	r, _ := newReconciler(t,
+		testChannel(multisuseiov1alpha1.DriftPolicyRemediate),
+		testBundleObject(t),
+		fleetCluster("gpu-a", map[string]string{"gpu": "nvidia"}),
+		modifiedDeployment("gpu-a"),
+	)
+	req := ctrl.Request{NamespacedName: client.ObjectKey{Name: "nvidia-stable"}}
+
+	_, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{0, 1}, targetGenerations(t, r.Client))
+
+	channel := &multisuseiov1alpha1.Channel{}
+	require.NoError(t, r.Get(context.Background(), req.NamespacedName, channel))
+	require.NotNil(t, channel.Status.LastRemediation)
+	assert.True(t, meta.IsStatusConditionTrue(channel.Status.Conditions, driftConditionType))
+
+	// The drift persists while Fleet redeploys; it is not remediated again during the cooldown
+	_, err = r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{0, 1}, targetGenerations(t, r.Client))
+}
+
+func TestReconcile_RecordsDriftWhenForceSyncFails(t *testing.T) {
+	// The cluster matches the Channel but none of the Bundle targets
+	bundle := testBundleObject(t)
+	require.NoError(t, unstructured.SetNestedSlice(bundle.Object, []interface{}{}, "spec", "targets"))
+	r, _ := newReconciler(t,
+		testChannel(multisuseiov1alpha1.DriftPolicyRemediate),
+		bundle,
+		fleetCluster("gpu-a", map[string]string{"gpu": "nvidia"}),
+		modifiedDeployment("gpu-a"),
+	)
+	req := ctrl.Request{NamespacedName: client.ObjectKey{Name: "nvidia-stable"}}
+
+	_, err := r.Reconcile(context.Background(), req)
+	assert.ErrorContains(t, err, "no target of Bundle rmc-nvidia-stable deploys cluster gpu-a")
+
+	channel := &multisuseiov1alpha1.Channel{}
+	require.NoError(t, r.Get(context.Background(), req.NamespacedName, channel))
+	assert.True(t, meta.IsStatusConditionTrue(channel.Status.Conditions, driftConditionType))
+	assert.Equal(t, []multisuseiov1alpha1.ClusterDrift{
+		{Cluster: "gpu-a", Type: string(drift.TypeModifiedResources), Message: "resources modified outside of Fleet"},
+	}, channel.Status.Drift)
+	assert.Nil(t, channel.Status.LastRemediation)
+}
+
+func TestReconcile_DriftCheckFailureUpdatesStatusOnce(t *testing.T) {
+	r, _ := newReconciler(t, testChannel(multisuseiov1alpha1.DriftPolicyReport))
+	r.VersionResolver = staticResolver{}
+	req := ctrl.Request{NamespacedName: client.ObjectKey{Name: "nvidia-stable"}}
+
+	_, err := r.Reconcile(context.Background(), req)
+	assert.ErrorContains(t, err, "no version pins for vendor nvidia in channel stable")
+	channel := &multisuseiov1alpha1.Channel{}
+	require.NoError(t, r.Get(context.Background(), req.NamespacedName, channel))
+	condition := meta.FindStatusCondition(channel.Status.Conditions, driftConditionType)
+	require.NotNil(t, condition)
+	assert.Equal(t, "DriftCheckFailed", condition.Reason)
+
+	// Failing again the same way leaves the status, and the Channel's resourceVersion, untouched
+	_, err = r.Reconcile(context.Background(), req)
+	assert.Error(t, err)
+	again := &multisuseiov1alpha1.Channel{}
+	require.NoError(t, r.Get(context.Background(), req.NamespacedName, again))
+	assert.Equal(t, channel.ResourceVersion, again.ResourceVersion)
+}
+
+func TestReconcile_PausedChannelDriftsFromCurrentPins(t *testing.T) {
+	heldPins := versions.Pins{OperatorTag: "v24.6.0", RuntimeTag: "12.4.0", ChartVersion: "v24.6.0"}
+	// deployed returns an unmodified BundleDeployment of cluster deploying pins
+	deployed := func(cluster string, pins versions.Pins) *unstructured.Unstructured {
+		bd := modifiedDeployment(cluster)
+		require.NoError(t, unstructured.SetNestedField(bd.Object, pins.ChartVersion, "spec", "options", "helm", "version"))
+		require.NoError(t, unstructured.SetNestedField(bd.Object, pins.HelmValues(), "spec", "options", "helm", "values"))
+		require.NoError(t, unstructured.SetNestedField(bd.Object, "Ready", "status", "display", "state"))
+		return bd
+	}
+	channel := testChannel(multisuseiov1alpha1.DriftPolicyReport)
+	channel.Spec.Paused = true
+	channel.Status.CurrentPins = &multisuseiov1alpha1.PinnedVersion{
+		OperatorTag:  heldPins.OperatorTag,
+		RuntimeTag:   heldPins.RuntimeTag,
+		ChartVersion: heldPins.ChartVersion,
+	}
+	r, _ := newReconciler(t,
+		channel,
+		testBundleObject(t),
+		fleetCluster("gpu-a", map[string]string{"gpu": "nvidia"}),
+		fleetCluster("gpu-b", map[string]string{"gpu": "nvidia"}),
+		deployed("gpu-a", heldPins),
+		deployed("gpu-b", testPins),
+	)
+	req := ctrl.Request{NamespacedName: client.ObjectKey{Name: "nvidia-stable"}}
+
+	_, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	// The newly resolved pins are held back, so only the cluster off the held version drifted
+	require.NoError(t, r.Get(context.Background(), req.NamespacedName, channel))
+	require.Len(t, channel.Status.Drift, 1)
+	assert.Equal(t, "gpu-b", channel.Status.Drift[0].Cluster)
+	assert.Equal(t, string(drift.TypeVersionMismatch), channel.Status.Drift[0].Type)
+	assert.Contains(t, channel.Status.Drift[0].Message, `version: expected "v24.6.0", deployed "v24.9.0"`)
+}
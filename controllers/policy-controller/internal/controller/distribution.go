@@ -0,0 +1,222 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	multisuseiov1alpha1 "github.com/suse/rancher-multi-compute/api/multi.suse.io/v1alpha1"
+	"github.com/suse/rancher-multi-compute/internal/fleetutil"
+	"github.com/suse/rancher-multi-compute/internal/policies"
+)
+
+// GVKs for Fleet
+var (
+	bundleGVK = schema.GroupVersionKind{
+		Group:   "fleet.cattle.io",
+		Version: "v1alpha1",
+		Kind:    "Bundle",
+	}
+	bdGVK = schema.GroupVersionKind{
+		Group:   "fleet.cattle.io",
+		Version: "v1alpha1",
+		Kind:    "BundleDeployment",
+	}
+)
+
+const (
+	fleetSystemNamespace = "cattle-fleet-system"
+	// policiesBundlePrefix names the Bundles distributing the policies of a MultiComputeConfig
+	policiesBundlePrefix = "rmc-policies-"
+	// templatesBundleSuffix names the Bundle deploying the Gatekeeper templates the constraints depend on
+	templatesBundleSuffix = "-templates"
+	// maxBundleNameLength keeps Bundle names usable as the fleet.cattle.io/bundle-name label value
+	maxBundleNameLength = 63
+	// maxClusterStates bounds the per-cluster entries kept in status
+	maxClusterStates = 50
+)
+
+// reconcileDistribution deploys the enabled policies to the downstream clusters selected by the
+// MultiComputeConfig through Fleet Bundles and returns their rollout, nil when not distributed.
+// Gatekeeper templates are deployed by a separate Bundle the constraints depend on, as their kinds
+// must exist before constraints can be installed.
+func (r *MultiComputeConfigReconciler) reconcileDistribution(ctx context.Context, config *multisuseiov1alpha1.MultiComputeConfig) (*multisuseiov1alpha1.PolicyDistributionStatus, error) {
+	name := distributionBundleName(config)
+	templatesName := name + templatesBundleSuffix
+	enabled := policies.Enabled(config.Spec.Policies)
+	if config.Spec.Policies.ClusterSelector == nil || len(enabled) == 0 {
+		for _, bundle := range []string{name, templatesName} {
+			if err := r.deleteBundle(ctx, bundle); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	}
+
+	engine := policies.Engine(config.Spec.Policies)
+	if engine == multisuseiov1alpha1.PolicyEngineWebhook {
+		// The webhook only serves the management cluster, see setDistributedCondition. Retrying
+		// cannot help until the engine or the cluster selector changes.
+		for _, bundle := range []string{name, templatesName} {
+			if err := r.deleteBundle(ctx, bundle); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	}
+	var templates, objects []*unstructured.Unstructured
+	for _, policy := range enabled {
+		rendered, err := policies.Render(engine, policy, policyName(config, policy), config.Spec.Policies)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range rendered {
+			obj.SetLabels(map[string]string{partOfLabelKey: partOfLabelValue})
+			if obj.GroupVersionKind() == policies.ConstraintTemplateGVK {
+				templates = append(templates, obj)
+			} else {
+				objects = append(objects, obj)
+			}
+		}
+	}
+
+	targets := fleetutil.ConvertLabelSelectorToTargets(*config.Spec.Policies.ClusterSelector, nil)
+	var dependsOn []interface{}
+	if len(templates) > 0 {
+		if err := r.applyBundle(ctx, config, templatesName, templates, targets, nil); err != nil {
+			return nil, err
+		}
+		dependsOn = []interface{}{map[string]interface{}{"name": templatesName}}
+	} else if err := r.deleteBundle(ctx, templatesName); err != nil {
+		return nil, err
+	}
+	if err := r.applyBundle(ctx, config, name, objects, targets, dependsOn); err != nil {
+		return nil, err
+	}
+
+	return r.distributionStatus(ctx, name)
+}
+
+// applyBundle server-side applies a Bundle deploying objects to the targeted clusters
+func (r *MultiComputeConfigReconciler) applyBundle(ctx context.Context, config *multisuseiov1alpha1.MultiComputeConfig, name string, objects []*unstructured.Unstructured, targets []fleetutil.Target, dependsOn []interface{}) error {
+	resources, err := fleetutil.ResourcesFromObjects(objects)
+	if err != nil {
+		return err
+	}
+	fleetTargets, err := fleetutil.TargetsToUnstructured(targets)
+	if err != nil {
+		return err
+	}
+	spec := map[string]interface{}{
+		"resources": fleetutil.ResourcesToUnstructured(resources),
+		"targets":   fleetTargets,
+	}
+	if len(dependsOn) > 0 {
+		spec["dependsOn"] = dependsOn
+	}
+
+	b := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	b.SetGroupVersionKind(bundleGVK)
+	b.SetNamespace(fleetSystemNamespace)
+	b.SetName(name)
+	b.SetLabels(map[string]string{
+		partOfLabelKey: partOfLabelValue,
+		ownerLabelKey:  config.Name,
+	})
+	// OwnerReference to MultiComputeConfig (cluster-scoped → leave Namespace empty)
+	b.SetOwnerReferences([]metav1.OwnerReference{{
+		APIVersion: "multi.suse.io/v1alpha1",
+		Kind:       "MultiComputeConfig",
+		Name:       config.Name,
+		UID:        config.UID,
+	}})
+
+	if err := r.Apply(ctx, client.ApplyConfigurationFromUnstructured(b), client.FieldOwner(fieldOwner), client.ForceOwnership); err != nil {
+		if meta.IsNoMatchError(err) {
+			return fmt.Errorf("failed to apply Bundle %s, is Fleet installed? %w", name, err)
+		}
+		return fmt.Errorf("failed to apply Bundle %s: %w", name, err)
+	}
+	return nil
+}
+
+// deleteBundle deletes a Bundle no longer distributing policies, if any
+func (r *MultiComputeConfigReconciler) deleteBundle(ctx context.Context, name string) error {
+	b := &unstructured.Unstructured{}
+	b.SetGroupVersionKind(bundleGVK)
+	b.SetNamespace(fleetSystemNamespace)
+	b.SetName(name)
+	if err := r.Delete(ctx, b); err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return fmt.Errorf("failed to delete Bundle %s: %w", name, err)
+	}
+	return nil
+}
+
+// distributionStatus rolls the BundleDeployments of the policies Bundle up into per-cluster state
+func (r *MultiComputeConfigReconciler) distributionStatus(ctx context.Context, name string) (*multisuseiov1alpha1.PolicyDistributionStatus, error) {
+	bds := &unstructured.UnstructuredList{}
+	bds.SetGroupVersionKind(bdGVK)
+	if err := r.List(ctx, bds, client.MatchingLabels{
+		fleetutil.BundleNameLabel:      name,
+		fleetutil.BundleNamespaceLabel: fleetSystemNamespace,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list BundleDeployments of Bundle %s: %w", name, err)
+	}
+	states := make([]fleetutil.BundleDeploymentState, 0, len(bds.Items))
+	for i := range bds.Items {
+		states = append(states, fleetutil.ParseBundleDeployment(&bds.Items[i]))
+	}
+
+	// The Bundle may not have been processed by Fleet yet
+	desired := 0
+	b := &unstructured.Unstructured{}
+	b.SetGroupVersionKind(bundleGVK)
+	if err := r.Get(ctx, client.ObjectKey{Namespace: fleetSystemNamespace, Name: name}, b); err == nil {
+		d, _, _ := unstructured.NestedInt64(b.Object, "status", "summary", "desiredReady")
+		desired = int(d)
+	}
+
+	rollout := fleetutil.SummarizeRollout(states, desired)
+	status := &multisuseiov1alpha1.PolicyDistributionStatus{
+		Bundle:          name,
+		DesiredClusters: int32(rollout.Desired),
+		ReadyClusters:   int32(rollout.Ready),
+		FailedClusters:  int32(rollout.Failed),
+		PendingClusters: int32(rollout.Pending),
+	}
+	for i, c := range rollout.Clusters {
+		if i == maxClusterStates {
+			break
+		}
+		status.Clusters = append(status.Clusters, multisuseiov1alpha1.ClusterState{
+			Cluster: c.Cluster,
+			Ready:   c.Ready,
+			State:   c.State,
+			Message: c.Message,
+		})
+	}
+	return status, nil
+}
+
+// distributionBundleName returns the name of the Bundle distributing the policies of a
+// MultiComputeConfig. Long names are truncated and suffixed with a hash to stay a valid label
+// value once the templates suffix is added.
+func distributionBundleName(config *multisuseiov1alpha1.MultiComputeConfig) string {
+	name := policiesBundlePrefix + config.Name
+	limit := maxBundleNameLength - len(templatesBundleSuffix)
+	if len(name) <= limit {
+		return name
+	}
+	sum := sha256.Sum256([]byte(config.Name))
+	suffix := hex.EncodeToString(sum[:])[:8]
+	return strings.TrimRight(name[:limit-len(suffix)-1], "-.") + "-" + suffix
+}
@@ -0,0 +1,186 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	multisuseiov1alpha1 "github.com/suse/rancher-multi-compute/api/multi.suse.io/v1alpha1"
+	"github.com/suse/rancher-multi-compute/internal/fleetutil"
+	"github.com/suse/rancher-multi-compute/internal/testutil"
+)
+
+// distributionReconciler returns a reconciler whose fake client holds objects
+func distributionReconciler(t *testing.T, objects ...client.Object) *MultiComputeConfigReconciler {
+	c := testutil.NewFakeClient(t, objects...)
+	return &MultiComputeConfigReconciler{Client: c, Scheme: c.Scheme()}
+}
+
+func distributedConfig(engine string) *multisuseiov1alpha1.MultiComputeConfig {
+	return &multisuseiov1alpha1.MultiComputeConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "global-config", UID: "1234"},
+		Spec: multisuseiov1alpha1.MultiComputeConfigSpec{
+			Policies: multisuseiov1alpha1.PolicyConfig{
+				Engine:          engine,
+				LimitGPUsPerPod: 2,
+				ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+			},
+		},
+	}
+}
+
+// getBundle returns the Bundle named name, nil if it does not exist
+func getBundle(t *testing.T, r *MultiComputeConfigReconciler, name string) *unstructured.Unstructured {
+	b := &unstructured.Unstructured{}
+	b.SetGroupVersionKind(bundleGVK)
+	err := r.Get(context.Background(), client.ObjectKey{Namespace: fleetSystemNamespace, Name: name}, b)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	require.NoError(t, err)
+	return b
+}
+
+// bundleResources returns the names of the resources a Bundle deploys
+func bundleResources(t *testing.T, b *unstructured.Unstructured) []string {
+	resources, _, err := unstructured.NestedSlice(b.Object, "spec", "resources")
+	require.NoError(t, err)
+	names := make([]string, 0, len(resources))
+	for _, resource := range resources {
+		names = append(names, resource.(map[string]interface{})["name"].(string))
+	}
+	return names
+}
+
+func policiesDeployment(cluster string, ready bool) *unstructured.Unstructured {
+	bd := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"ready":   ready,
+			"display": map[string]interface{}{"state": "Ready"},
+		},
+	}}
+	bd.SetGroupVersionKind(bdGVK)
+	bd.SetNamespace("cluster-fleet-default-" + cluster)
+	bd.SetName("rmc-policies-global-config")
+	bd.SetLabels(map[string]string{
+		fleetutil.BundleNameLabel:      "rmc-policies-global-config",
+		fleetutil.BundleNamespaceLabel: fleetSystemNamespace,
+		fleetutil.ClusterLabel:         cluster,
+	})
+	return bd
+}
+
+func TestReconcileDistribution(t *testing.T) {
+	r := distributionReconciler(t, policiesDeployment("prod-a", true), policiesDeployment("prod-b", false))
+	config := distributedConfig(multisuseiov1alpha1.PolicyEngineKyverno)
+
+	status, err := r.reconcileDistribution(context.Background(), config)
+	require.NoError(t, err)
+
+	bundle := getBundle(t, r, "rmc-policies-global-config")
+	require.NotNil(t, bundle)
+	assert.Equal(t, "global-config", bundle.GetLabels()[ownerLabelKey])
+	require.Len(t, bundle.GetOwnerReferences(), 1)
+	assert.Equal(t, "MultiComputeConfig", bundle.GetOwnerReferences()[0].Kind)
+	assert.Equal(t, []string{"clusterpolicy-rmc-global-config-limit-gpus-per-pod.yaml"}, bundleResources(t, bundle))
+	targets, _, _ := unstructured.NestedSlice(bundle.Object, "spec", "targets")
+	require.Len(t, targets, 1)
+	selector, _, _ := unstructured.NestedStringMap(targets[0].(map[string]interface{}), "clusterSelector", "matchLabels")
+	assert.Equal(t, config.Spec.Policies.ClusterSelector.MatchLabels, selector)
+	_, found, _ := unstructured.NestedSlice(bundle.Object, "spec", "dependsOn")
+	assert.False(t, found)
+	assert.Nil(t, getBundle(t, r, "rmc-policies-global-config-templates"))
+
+	require.NotNil(t, status)
+	assert.Equal(t, "rmc-policies-global-config", status.Bundle)
+	assert.Equal(t, int32(2), status.DesiredClusters)
+	assert.Equal(t, int32(1), status.ReadyClusters)
+	assert.Len(t, status.Clusters, 2)
+}
+
+func TestReconcileDistribution_GatekeeperTemplates(t *testing.T) {
+	r := distributionReconciler(t)
+	config := distributedConfig(multisuseiov1alpha1.PolicyEngineGatekeeper)
+
+	_, err := r.reconcileDistribution(context.Background(), config)
+	require.NoError(t, err)
+
+	// The templates are deployed first, the constraints depend on their Bundle
+	templates := getBundle(t, r, "rmc-policies-global-config-templates")
+	require.NotNil(t, templates)
+	assert.Equal(t, []string{"constrainttemplate-k8sgpulimit.yaml"}, bundleResources(t, templates))
+	bundle := getBundle(t, r, "rmc-policies-global-config")
+	require.NotNil(t, bundle)
+	assert.Equal(t, []string{"k8sgpulimit-rmc-global-config-limit-gpus-per-pod.yaml"}, bundleResources(t, bundle))
+	dependsOn, _, _ := unstructured.NestedSlice(bundle.Object, "spec", "dependsOn")
+	assert.Equal(t, []interface{}{map[string]interface{}{"name": "rmc-policies-global-config-templates"}}, dependsOn)
+
+	// Switching to Kyverno drops the templates Bundle and the dependency
+	config.Spec.Policies.Engine = multisuseiov1alpha1.PolicyEngineKyverno
+	_, err = r.reconcileDistribution(context.Background(), config)
+	require.NoError(t, err)
+	assert.Nil(t, getBundle(t, r, "rmc-policies-global-config-templates"))
+	bundle = getBundle(t, r, "rmc-policies-global-config")
+	_, found, _ := unstructured.NestedSlice(bundle.Object, "spec", "dependsOn")
+	assert.False(t, found)
+}
+
+func TestReconcileDistribution_ClusterSelectorRemoved(t *testing.T) {
+	r := distributionReconciler(t)
+	config := distributedConfig(multisuseiov1alpha1.PolicyEngineGatekeeper)
+	_, err := r.reconcileDistribution(context.Background(), config)
+	require.NoError(t, err)
+	require.NotNil(t, getBundle(t, r, "rmc-policies-global-config"))
+
+	config.Spec.Policies.ClusterSelector = nil
+	status, err := r.reconcileDistribution(context.Background(), config)
+	require.NoError(t, err)
+	assert.Nil(t, status)
+	assert.Nil(t, getBundle(t, r, "rmc-policies-global-config"))
+	assert.Nil(t, getBundle(t, r, "rmc-policies-global-config-templates"))
+}
+
+func TestReconcileDistribution_WebhookEngine(t *testing.T) {
+	r := distributionReconciler(t)
+	config := distributedConfig(multisuseiov1alpha1.PolicyEngineKyverno)
+	_, err := r.reconcileDistribution(context.Background(), config)
+	require.NoError(t, err)
+
+	// The webhook cannot be distributed, which retrying would not change
+	config.Spec.Policies.Engine = multisuseiov1alpha1.PolicyEngineWebhook
+	status, err := r.reconcileDistribution(context.Background(), config)
+	require.NoError(t, err)
+	assert.Nil(t, status)
+	assert.Nil(t, getBundle(t, r, "rmc-policies-global-config"))
+
+	config.Status.Distribution = status
+	setDistributedCondition(config)
+	condition := meta.FindStatusCondition(config.Status.Conditions, distributedConditionType)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, "UnsupportedEngine", condition.Reason)
+
+	// Without a cluster selector there is nothing to report
+	config.Spec.Policies.ClusterSelector = nil
+	setDistributedCondition(config)
+	assert.Nil(t, meta.FindStatusCondition(config.Status.Conditions, distributedConditionType))
+}
+
+func TestDistributionBundleName(t *testing.T) {
+	config := &multisuseiov1alpha1.MultiComputeConfig{ObjectMeta: metav1.ObjectMeta{Name: "global-config"}}
+	assert.Equal(t, "rmc-policies-global-config", distributionBundleName(config))
+
+	config.Name = "a-very-long-multi-compute-config-name-for-the-research-department-gpus"
+	name := distributionBundleName(config)
+	assert.LessOrEqual(t, len(name+templatesBundleSuffix), maxBundleNameLength)
+	assert.NotEqual(t, name, distributionBundleName(&multisuseiov1alpha1.MultiComputeConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: config.Name + "-2"},
+	}))
+}
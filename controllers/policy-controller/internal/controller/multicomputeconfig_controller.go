@@ -2,16 +2,34 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	multisuseiov1alpha1 "github.com/suse/rancher-multi-compute/api/multi.suse.io/v1alpha1"
+	"github.com/suse/rancher-multi-compute/internal/policies"
+)
+
+const (
+	// distributedConditionType reports the deployment of the policies to downstream clusters
+	distributedConditionType = "Distributed"
+	// distributionRequeueInterval is how often a distribution in progress is checked
+	distributionRequeueInterval = 30 * time.Second
+	// fieldOwner is the server-side apply field manager of the rendered policies
+	fieldOwner       = "policy-controller"
+	policyNamePrefix = "rmc-"
+	ownerLabelKey    = "multi.suse.io/owner"
+	partOfLabelKey   = "app.kubernetes.io/part-of"
+	partOfLabelValue = "rancher-multi-compute"
 )
 
 // MultiComputeConfigReconciler reconciles a MultiComputeConfig object
@@ -25,6 +43,11 @@ type MultiComputeConfigReconciler struct {
 //+kubebuilder:rbac:groups=multi.suse.io,resources=multicomputeconfigs/finalizers,verbs=update
 //+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups=kyverno.io,resources=clusterpolicies,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=fleet.cattle.io,resources=bundles,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=fleet.cattle.io,resources=bundledeployments,verbs=get;list;watch
+//+kubebuilder:rbac:groups=templates.gatekeeper.sh,resources=constrainttemplates,verbs=get;list;watch;create;update;patch
+//+kubebuilder:rbac:groups=constraints.gatekeeper.sh,resources=k8sgpulimit;k8sgpunamespaces;k8srequiredruntimeclass,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop
 func (r *MultiComputeConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -40,21 +63,41 @@ func (r *MultiComputeConfigReconciler) Reconcile(ctx context.Context, req ctrl.R
 	}
 
 	// Apply org-wide compute policies
-	if err := r.applyPolicies(ctx, config); err != nil {
+	applied, err := r.applyPolicies(ctx, config)
+	if err != nil {
 		logger.Error(err, "failed to apply policies", "config", config.Name)
+		r.setReadyCondition(config, metav1.ConditionFalse, "PolicyApplyError", err.Error())
+		if statusErr := r.Status().Update(ctx, config); statusErr != nil {
+			logger.Error(statusErr, "failed to update MultiComputeConfig status")
+		}
 		return ctrl.Result{}, err
 	}
 
 	// Update status
-	config.Status.Conditions = []metav1.Condition{
-		{
-			Type:               "Ready",
-			Status:             metav1.ConditionTrue,
-			Reason:             "PoliciesApplied",
-			Message:            "All policies applied successfully",
-			LastTransitionTime: metav1.Now(),
-		},
+	message := "No policies enabled"
+	if len(applied) > 0 {
+		message = "Applied policies: " + strings.Join(applied, ", ")
 	}
+	r.setReadyCondition(config, metav1.ConditionTrue, "PoliciesApplied", message)
+
+	// Distribute the policies to the downstream clusters
+	distribution, err := r.reconcileDistribution(ctx, config)
+	if err != nil {
+		logger.Error(err, "failed to distribute policies", "config", config.Name)
+		meta.SetStatusCondition(&config.Status.Conditions, metav1.Condition{
+			Type:               distributedConditionType,
+			Status:             metav1.ConditionFalse,
+			Reason:             "DistributionError",
+			Message:            err.Error(),
+			ObservedGeneration: config.Generation,
+		})
+		if statusErr := r.Status().Update(ctx, config); statusErr != nil {
+			logger.Error(statusErr, "failed to update MultiComputeConfig status")
+		}
+		return ctrl.Result{}, err
+	}
+	config.Status.Distribution = distribution
+	setDistributedCondition(config)
 
 	if err := r.Status().Update(ctx, config); err != nil {
 		logger.Error(err, "failed to update MultiComputeConfig status")
@@ -62,39 +105,158 @@ func (r *MultiComputeConfigReconciler) Reconcile(ctx context.Context, req ctrl.R
 	}
 
 	logger.Info("MultiComputeConfig reconciled successfully", "config", config.Name)
+	if d := config.Status.Distribution; d != nil && d.ReadyClusters < d.DesiredClusters {
+		return ctrl.Result{RequeueAfter: distributionRequeueInterval}, nil
+	}
 	return ctrl.Result{RequeueAfter: 5 * time.Minute}, nil
 }
 
-// applyPolicies applies the configured policies
-func (r *MultiComputeConfigReconciler) applyPolicies(ctx context.Context, config *multisuseiov1alpha1.MultiComputeConfig) error {
-	// This is a simplified policy application
-	// In a real implementation, you would:
-	// 1. Apply Gatekeeper constraints
-	// 2. Apply Kyverno policies
-	// 3. Configure resource quotas
-	// 4. Set up network policies
+// setDistributedCondition summarizes the distribution of the policies to downstream clusters
+func setDistributedCondition(config *multisuseiov1alpha1.MultiComputeConfig) {
+	d := config.Status.Distribution
+	if d == nil {
+		engine := policies.Engine(config.Spec.Policies)
+		if config.Spec.Policies.ClusterSelector != nil && engine == multisuseiov1alpha1.PolicyEngineWebhook &&
+			len(policies.Enabled(config.Spec.Policies)) > 0 {
+			meta.SetStatusCondition(&config.Status.Conditions, metav1.Condition{
+				Type:   distributedConditionType,
+				Status: metav1.ConditionFalse,
+				Reason: "UnsupportedEngine",
+				Message: fmt.Sprintf("policies enforced by the %s engine cannot be distributed to downstream clusters, use %s or %s",
+					engine, multisuseiov1alpha1.PolicyEngineKyverno, multisuseiov1alpha1.PolicyEngineGatekeeper),
+				ObservedGeneration: config.Generation,
+			})
+			return
+		}
+		meta.RemoveStatusCondition(&config.Status.Conditions, distributedConditionType)
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:               distributedConditionType,
+		Status:             metav1.ConditionUnknown,
+		Reason:             "Distributing",
+		Message:            fmt.Sprintf("%d/%d clusters ready", d.ReadyClusters, d.DesiredClusters),
+		ObservedGeneration: config.Generation,
+	}
+	switch {
+	case d.FailedClusters > 0:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "DistributionFailed"
+		condition.Message = fmt.Sprintf("%d/%d clusters failed", d.FailedClusters, d.DesiredClusters)
+	case d.DesiredClusters > 0 && d.ReadyClusters == d.DesiredClusters:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "Distributed"
+	}
+	meta.SetStatusCondition(&config.Status.Conditions, condition)
+}
+
+// setReadyCondition records the outcome of applying the policies
+func (r *MultiComputeConfigReconciler) setReadyCondition(config *multisuseiov1alpha1.MultiComputeConfig, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&config.Status.Conditions, metav1.Condition{
+		Type:               "Ready",
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: config.Generation,
+	})
+}
 
+// applyPolicies server-side applies the objects enforcing the enabled policies with the configured
+// engine, and deletes those of disabled policies and of the other engines. It returns the names of
+// the applied ClusterPolicies or constraints, or of the policies enforced by the webhook engine.
+func (r *MultiComputeConfigReconciler) applyPolicies(ctx context.Context, config *multisuseiov1alpha1.MultiComputeConfig) ([]string, error) {
 	logger := log.FromContext(ctx)
 
-	if config.Spec.Policies.EnforceRuntimeClass {
-		logger.Info("Enforcing runtime class policy")
+	engine := policies.Engine(config.Spec.Policies)
+	enabled := map[string]bool{}
+	for _, policy := range policies.Enabled(config.Spec.Policies) {
+		enabled[policy] = true
 	}
 
-	if config.Spec.Policies.RestrictGPUNamespaces {
-		logger.Info("Restricting GPU namespaces")
+	var applied []string
+	for _, policy := range policies.Names {
+		name := policyName(config, policy)
+		for _, e := range policies.Engines {
+			if e == engine && enabled[policy] {
+				continue
+			}
+			if err := r.deletePolicy(ctx, e, policy, name); err != nil {
+				return nil, err
+			}
+		}
+		if !enabled[policy] {
+			continue
+		}
+		if engine == multisuseiov1alpha1.PolicyEngineWebhook {
+			// The webhook evaluates pods against the MultiComputeConfig itself
+			applied = append(applied, policy)
+			continue
+		}
+
+		objects, err := policies.Render(engine, policy, name, config.Spec.Policies)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range objects {
+			if err := r.applyPolicyObject(ctx, config, obj); err != nil {
+				return nil, err
+			}
+		}
+		applied = append(applied, name)
 	}
 
 	if config.Spec.Policies.RequireCosign {
 		logger.Info("Requiring Cosign signatures")
 	}
 
-	if config.Spec.Policies.LimitGPUsPerPod > 0 {
-		logger.Info("Limiting GPUs per pod", "limit", config.Spec.Policies.LimitGPUsPerPod)
+	return applied, nil
+}
+
+// applyPolicyObject server-side applies an object rendered for a policy. Objects shared by the
+// MultiComputeConfigs, such as constraint templates, are not owned by config.
+func (r *MultiComputeConfigReconciler) applyPolicyObject(ctx context.Context, config *multisuseiov1alpha1.MultiComputeConfig, obj *unstructured.Unstructured) error {
+	labels := map[string]string{partOfLabelKey: partOfLabelValue}
+	if obj.GroupVersionKind() != policies.ConstraintTemplateGVK {
+		labels[ownerLabelKey] = config.Name
+		obj.SetOwnerReferences([]metav1.OwnerReference{{
+			APIVersion: "multi.suse.io/v1alpha1",
+			Kind:       "MultiComputeConfig",
+			Name:       config.Name,
+			UID:        config.UID,
+		}})
+	}
+	obj.SetLabels(labels)
+
+	kind, name := obj.GetKind(), obj.GetName()
+	if err := r.Apply(ctx, client.ApplyConfigurationFromUnstructured(obj), client.FieldOwner(fieldOwner), client.ForceOwnership); err != nil {
+		if meta.IsNoMatchError(err) {
+			// Gatekeeper creates the constraint kinds asynchronously from their templates
+			return fmt.Errorf("failed to apply %s %s, is the %s policy engine installed and its templates ready? %w",
+				kind, name, policies.Engine(config.Spec.Policies), err)
+		}
+		return fmt.Errorf("failed to apply %s %s: %w", kind, name, err)
 	}
+	return nil
+}
 
+// deletePolicy deletes the object an engine enforces a disabled policy with, if any
+func (r *MultiComputeConfigReconciler) deletePolicy(ctx context.Context, engine, policy, name string) error {
+	obj, err := policies.Owned(engine, policy, name)
+	if err != nil {
+		return err
+	}
+	if err := r.Delete(ctx, obj); err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return fmt.Errorf("failed to delete %s %s: %w", obj.GetKind(), name, err)
+	}
 	return nil
 }
 
+// policyName returns the name of the object enforcing a policy of a MultiComputeConfig
+func policyName(config *multisuseiov1alpha1.MultiComputeConfig, policy string) string {
+	return policyNamePrefix + config.Name + "-" + policy
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *MultiComputeConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
@@ -0,0 +1,130 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	multisuseiov1alpha1 "github.com/suse/rancher-multi-compute/api/multi.suse.io/v1alpha1"
+	"github.com/suse/rancher-multi-compute/internal/policies"
+)
+
+// The webhook fails open, so that pods, including the policy-controller's own, can still be created
+// while it is unavailable.
+//+kubebuilder:webhook:path=/validate--v1-pod,mutating=false,failurePolicy=ignore,sideEffects=None,groups="",resources=pods,verbs=create,versions=v1,name=vpod.multi.suse.io,admissionReviewVersions=v1
+
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+
+// PodValidator enforces on pods the policies of the MultiComputeConfigs using the webhook engine.
+// Violations are rejected, or only returned as warnings by configs in audit mode.
+type PodValidator struct {
+	client.Reader
+}
+
+var _ admission.CustomValidator = &PodValidator{}
+
+// SetupPodWebhookWithManager registers the pod validating webhook with the Manager
+func SetupPodWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&corev1.Pod{}).
+		WithValidator(&PodValidator{Reader: mgr.GetClient()}).
+		Complete()
+}
+
+// ValidateCreate validates a pod against the policies of every MultiComputeConfig using the webhook engine
+func (v *PodValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return nil, fmt.Errorf("expected a Pod, got %T", obj)
+	}
+	if len(policies.PodGPUs(pod)) == 0 {
+		return nil, nil
+	}
+
+	configs := &multisuseiov1alpha1.MultiComputeConfigList{}
+	if err := v.List(ctx, configs); err != nil {
+		return nil, fmt.Errorf("failed to list MultiComputeConfigs: %w", err)
+	}
+
+	var (
+		warnings  admission.Warnings
+		denied    []string
+		namespace *corev1.Namespace
+	)
+	for _, config := range configs.Items {
+		if policies.Engine(config.Spec.Policies) != multisuseiov1alpha1.PolicyEngineWebhook {
+			continue
+		}
+		if namespace == nil && config.Spec.Policies.RestrictGPUNamespaces {
+			ns, err := v.namespace(ctx, pod)
+			if err != nil {
+				return nil, err
+			}
+			namespace = ns
+		}
+
+		violations, err := policies.Validate(pod, namespace, config.Spec.Policies)
+		if err != nil {
+			return nil, fmt.Errorf("failed to validate the policies of MultiComputeConfig %s: %w", config.Name, err)
+		}
+		for _, violation := range violations {
+			message := fmt.Sprintf("MultiComputeConfig %s: %s", config.Name, violation)
+			if config.Spec.Policies.Audit {
+				warnings = append(warnings, message)
+			} else {
+				denied = append(denied, message)
+			}
+		}
+	}
+
+	if len(warnings) > 0 {
+		log.FromContext(ctx).Info("pod violates audited policies", "namespace", pod.Namespace, "pod", podName(pod), "violations", warnings)
+	}
+	if len(denied) > 0 {
+		return warnings, fmt.Errorf("pod violates GPU policies: %s", strings.Join(denied, "; "))
+	}
+	return warnings, nil
+}
+
+// ValidateUpdate allows updates, as the resources and RuntimeClass of pods are immutable
+func (v *PodValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// ValidateDelete allows deletions
+func (v *PodValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// namespace returns the Namespace a pod is created in. Pods created by controllers may not have their
+// namespace set yet, in which case it is taken from the admission request.
+func (v *PodValidator) namespace(ctx context.Context, pod *corev1.Pod) (*corev1.Namespace, error) {
+	name := pod.Namespace
+	if name == "" {
+		req, err := admission.RequestFromContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		name = req.Namespace
+	}
+	namespace := &corev1.Namespace{}
+	if err := v.Get(ctx, client.ObjectKey{Name: name}, namespace); err != nil {
+		return nil, fmt.Errorf("failed to get Namespace %s: %w", name, err)
+	}
+	return namespace, nil
+}
+
+// podName returns the name of a pod, or its generated name prefix before it is named
+func podName(pod *corev1.Pod) string {
+	if pod.Name == "" {
+		return pod.GenerateName
+	}
+	return pod.Name
+}
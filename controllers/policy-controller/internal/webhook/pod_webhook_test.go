@@ -0,0 +1,99 @@
+package webhook
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	multisuseiov1alpha1 "github.com/suse/rancher-multi-compute/api/multi.suse.io/v1alpha1"
+)
+
+// gpuPod returns a pod in namespace limiting a GPU resource
+func gpuPod(namespace string, limits corev1.ResourceList) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{GenerateName: "gpu-", Namespace: namespace},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name:      "cuda",
+			Image:     "nvcr.io/nvidia/cuda:12.4.1-base-ubuntu22.04",
+			Resources: corev1.ResourceRequirements{Limits: limits},
+		}}},
+	}
+}
+
+func gpus(name string, quantity string) corev1.ResourceList {
+	return corev1.ResourceList{corev1.ResourceName(name): resource.MustParse(quantity)}
+}
+
+var _ = Describe("Pod Webhook (EnvTest)", func() {
+	var config *multisuseiov1alpha1.MultiComputeConfig
+
+	BeforeEach(func() {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ml-training"}}
+		Expect(client.IgnoreAlreadyExists(k8sClient.Create(ctx, ns))).To(Succeed())
+
+		config = &multisuseiov1alpha1.MultiComputeConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "webhook-config"},
+			Spec: multisuseiov1alpha1.MultiComputeConfigSpec{
+				Policies: multisuseiov1alpha1.PolicyConfig{
+					Engine:                multisuseiov1alpha1.PolicyEngineWebhook,
+					LimitGPUsPerPod:       2,
+					RestrictGPUNamespaces: true,
+					AllowedNamespaces:     []string{"ml-training"},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, config)).To(Succeed())
+		warnings.Take()
+	})
+
+	AfterEach(func() {
+		Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, config))).To(Succeed())
+	})
+
+	It("should reject pods requesting more GPUs than allowed across vendors", func() {
+		pod := gpuPod("ml-training", corev1.ResourceList{
+			"nvidia.com/gpu": resource.MustParse("2"),
+			"amd.com/gpu":    resource.MustParse("1"),
+		})
+		Eventually(func() error {
+			return k8sClient.Create(ctx, pod.DeepCopy())
+		}).Should(MatchError(ContainSubstring("pod requests 3 GPUs, more than the limit of 2")))
+
+		Expect(k8sClient.Create(ctx, gpuPod("ml-training", gpus("nvidia.com/gpu", "2")))).To(Succeed())
+	})
+
+	It("should reject GPU pods outside the allowed namespaces", func() {
+		Eventually(func() error {
+			return k8sClient.Create(ctx, gpuPod("default", gpus("gpu.intel.com/xe", "1")))
+		}).Should(Satisfy(apierrors.IsForbidden))
+
+		// Pods without GPUs are not restricted
+		Expect(k8sClient.Create(ctx, gpuPod("default", nil))).To(Succeed())
+	})
+
+	It("should only warn about violations in audit mode", func() {
+		config.Spec.Policies.Audit = true
+		Expect(k8sClient.Update(ctx, config)).To(Succeed())
+
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Create(ctx, gpuPod("default", gpus("nvidia.com/gpu", "4")))).To(Succeed())
+			g.Expect(warnings.Take()).To(ContainElements(
+				ContainSubstring("MultiComputeConfig webhook-config: limit-gpus-per-pod"),
+				ContainSubstring("MultiComputeConfig webhook-config: restrict-gpu-namespaces"),
+			))
+		}).Should(Succeed())
+	})
+
+	It("should ignore the MultiComputeConfigs of other engines", func() {
+		config.Spec.Policies.Engine = multisuseiov1alpha1.PolicyEngineKyverno
+		Expect(k8sClient.Update(ctx, config)).To(Succeed())
+
+		Eventually(func() error {
+			return k8sClient.Create(ctx, gpuPod("default", gpus("nvidia.com/gpu", "4")))
+		}).Should(Succeed())
+	})
+})
@@ -0,0 +1,195 @@
+package drift
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/suse/rancher-multi-compute/internal/fleetutil"
+)
+
+// Type classifies a drift finding
+type Type string
+
+const (
+	TypeVersionMismatch   Type = "VersionMismatch"
+	TypeMissingDeployment Type = "MissingDeployment"
+	TypeModifiedResources Type = "ModifiedResources"
+)
+
+// Finding describes drift detected on a single cluster
+type Finding struct {
+	Cluster string
+	Type    Type
+	Message string
+}
+
+// Expected is the Helm release a Channel intends to run on every targeted cluster
+type Expected struct {
+	Repo    string
+	Chart   string
+	Version string
+	Values  map[string]interface{}
+}
+
+// ExpectedRelease returns the release the Helm options of a Bundle describe
+func ExpectedRelease(helm *fleetutil.HelmOptions) Expected {
+	return Expected{Repo: helm.Repo, Chart: helm.Chart, Version: helm.Version, Values: helm.Values}
+}
+
+// WithoutOverridden returns the release without the values some clusters get from overrides,
+// so that clusters deploying an override are not reported as drifted on its keys
+func (e Expected) WithoutOverridden(overrides []fleetutil.ValuesOverride) Expected {
+	for _, override := range overrides {
+		e.Values = withoutKeys(e.Values, override.Values)
+	}
+	return e
+}
+
+// withoutKeys returns values without the leaves set in keys
+func withoutKeys(values, keys map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		nested, isMap := v.(map[string]interface{})
+		removed, overridden := keys[k]
+		removedMap, removesNested := removed.(map[string]interface{})
+		switch {
+		case !overridden:
+			out[k] = v
+		case isMap && removesNested:
+			out[k] = withoutKeys(nested, removedMap)
+		}
+	}
+	return out
+}
+
+// Detect compares the BundleDeployments of a Channel against its intent.
+// clusters lists the Fleet clusters the Channel targets; each of them must have a deployment.
+func Detect(expected Expected, clusters []string, deployments []fleetutil.BundleDeploymentState) []Finding {
+	var findings []Finding
+
+	deployed := make(map[string]bool, len(deployments))
+	for _, bd := range deployments {
+		deployed[bd.Cluster] = true
+	}
+	for _, cluster := range clusters {
+		if !deployed[cluster] {
+			findings = append(findings, Finding{
+				Cluster: cluster,
+				Type:    TypeMissingDeployment,
+				Message: "no BundleDeployment found for targeted cluster",
+			})
+		}
+	}
+
+	for _, bd := range deployments {
+		if diffs := compareRelease(expected, bd.Helm); len(diffs) > 0 {
+			findings = append(findings, Finding{
+				Cluster: bd.Cluster,
+				Type:    TypeVersionMismatch,
+				Message: strings.Join(diffs, "; "),
+			})
+		}
+		if len(bd.Modified) > 0 || bd.State == "Modified" {
+			msg := "resources modified outside of Fleet"
+			if len(bd.Modified) > 0 {
+				msg = strings.Join(bd.Modified, ", ")
+			}
+			findings = append(findings, Finding{
+				Cluster: bd.Cluster,
+				Type:    TypeModifiedResources,
+				Message: msg,
+			})
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Cluster < findings[j].Cluster
+	})
+	return findings
+}
+
+// maxSummarized bounds the findings listed by Summarize so condition messages stay short
+const maxSummarized = 10
+
+// Summarize renders findings as a single human readable line
+func Summarize(findings []Finding, targeted int) string {
+	if len(findings) == 0 {
+		return fmt.Sprintf("all %d targeted clusters match the channel intent", targeted)
+	}
+
+	clusters := map[string]bool{}
+	parts := make([]string, 0, maxSummarized+1)
+	for i, f := range findings {
+		clusters[f.Cluster] = true
+		if i < maxSummarized {
+			parts = append(parts, fmt.Sprintf("%s (%s)", f.Cluster, f.Type))
+		}
+	}
+	if len(findings) > maxSummarized {
+		parts = append(parts, fmt.Sprintf("and %d more", len(findings)-maxSummarized))
+	}
+	return fmt.Sprintf("%d of %d clusters drifted: %s", len(clusters), targeted, strings.Join(parts, ", "))
+}
+
+// compareRelease returns a description of every expected chart field or value the deployment does not match
+func compareRelease(expected Expected, actual fleetutil.HelmOptions) []string {
+	var diffs []string
+	if expected.Repo != "" && expected.Repo != actual.Repo {
+		diffs = append(diffs, fmt.Sprintf("repo: expected %q, deployed %q", expected.Repo, actual.Repo))
+	}
+	if expected.Chart != "" && expected.Chart != actual.Chart {
+		diffs = append(diffs, fmt.Sprintf("chart: expected %q, deployed %q", expected.Chart, actual.Chart))
+	}
+	if expected.Version != "" && expected.Version != actual.Version {
+		diffs = append(diffs, fmt.Sprintf("version: expected %q, deployed %q", expected.Version, actual.Version))
+	}
+
+	want := flatten("", expected.Values)
+	got := flatten("", actual.Values)
+	keys := make([]string, 0, len(want))
+	for k := range want {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if !reflect.DeepEqual(want[k], got[k]) {
+			diffs = append(diffs, fmt.Sprintf("%s: expected %v, deployed %v", k, want[k], display(got[k])))
+		}
+	}
+	return diffs
+}
+
+// flatten turns nested Helm values into dotted keys, normalizing leaves to strings
+func flatten(prefix string, values map[string]interface{}) map[string]interface{} {
+	out := map[string]interface{}{}
+	for k, v := range values {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		switch val := v.(type) {
+		case map[string]interface{}:
+			for fk, fv := range flatten(key, val) {
+				out[fk] = fv
+			}
+		case map[string]string:
+			for sk, sv := range val {
+				out[key+"."+sk] = sv
+			}
+		case nil:
+			out[key] = nil
+		default:
+			out[key] = fmt.Sprint(val)
+		}
+	}
+	return out
+}
+
+func display(v interface{}) interface{} {
+	if v == nil {
+		return "<unset>"
+	}
+	return v
+}
@@ -0,0 +1,164 @@
+package drift
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/suse/rancher-multi-compute/internal/fleetutil"
+)
+
+func expectedNVIDIA() Expected {
+	return Expected{
+		Repo:  "https://nvidia.github.io/helm-charts",
+		Chart: "gpu-operator",
+		Values: map[string]interface{}{
+			"image": map[string]interface{}{
+				"operatorTag": "v24.9.0",
+				"runtimeTag":  "12.4.1",
+			},
+		},
+	}
+}
+
+func deployment(cluster, operatorTag string) fleetutil.BundleDeploymentState {
+	return fleetutil.BundleDeploymentState{
+		Cluster:     cluster,
+		Ready:       true,
+		NonModified: true,
+		State:       "Ready",
+		Helm: fleetutil.HelmOptions{
+			Repo:  "https://nvidia.github.io/helm-charts",
+			Chart: "gpu-operator",
+			Values: map[string]interface{}{
+				"image": map[string]interface{}{
+					"operatorTag": operatorTag,
+					"runtimeTag":  "12.4.1",
+				},
+			},
+		},
+	}
+}
+
+func TestDetect_NoDrift(t *testing.T) {
+	findings := Detect(expectedNVIDIA(), []string{"gpu-a", "gpu-b"}, []fleetutil.BundleDeploymentState{
+		deployment("gpu-a", "v24.9.0"),
+		deployment("gpu-b", "v24.9.0"),
+	})
+
+	assert.Empty(t, findings)
+	assert.Equal(t, "all 2 targeted clusters match the channel intent", Summarize(findings, 2))
+}
+
+func TestDetect_VersionMismatch(t *testing.T) {
+	findings := Detect(expectedNVIDIA(), []string{"gpu-a"}, []fleetutil.BundleDeploymentState{
+		deployment("gpu-a", "v24.6.0"),
+	})
+
+	require.Len(t, findings, 1)
+	assert.Equal(t, "gpu-a", findings[0].Cluster)
+	assert.Equal(t, TypeVersionMismatch, findings[0].Type)
+	assert.Equal(t, "image.operatorTag: expected v24.9.0, deployed v24.6.0", findings[0].Message)
+}
+
+func TestDetect_ChartMismatchAndUnsetValue(t *testing.T) {
+	bd := deployment("gpu-a", "v24.9.0")
+	bd.Helm.Chart = "gpu-operator-legacy"
+	delete(bd.Helm.Values["image"].(map[string]interface{}), "runtimeTag")
+
+	findings := Detect(expectedNVIDIA(), nil, []fleetutil.BundleDeploymentState{bd})
+
+	require.Len(t, findings, 1)
+	assert.Equal(t, `chart: expected "gpu-operator", deployed "gpu-operator-legacy"; image.runtimeTag: expected 12.4.1, deployed <unset>`, findings[0].Message)
+}
+
+func TestDetect_ChartVersionMismatch(t *testing.T) {
+	expected := ExpectedRelease(&fleetutil.HelmOptions{
+		Chart:   "oci://registry.example.internal/charts/gpu-operator",
+		Version: "v24.9.0",
+		Values:  expectedNVIDIA().Values,
+	})
+	bd := deployment("gpu-a", "v24.9.0")
+	bd.Helm.Repo = ""
+	bd.Helm.Chart = "oci://registry.example.internal/charts/gpu-operator"
+	bd.Helm.Version = "v24.3.0"
+
+	findings := Detect(expected, nil, []fleetutil.BundleDeploymentState{bd})
+
+	require.Len(t, findings, 1)
+	assert.Equal(t, `version: expected "v24.9.0", deployed "v24.3.0"`, findings[0].Message)
+}
+
+func TestDetect_IgnoresOverriddenValues(t *testing.T) {
+	overridden := deployment("gpu-a", "v24.9.0")
+	overridden.Helm.Values["image"].(map[string]interface{})["runtimeTag"] = "12.6.0"
+	overridden.Helm.Values["mig"] = map[string]interface{}{"strategy": "mixed"}
+
+	expected := expectedNVIDIA()
+	expected.Values["mig"] = map[string]interface{}{"strategy": "single"}
+	expected = expected.WithoutOverridden([]fleetutil.ValuesOverride{{
+		Values: map[string]interface{}{
+			"image": map[string]interface{}{"runtimeTag": "12.6.0"},
+			"mig":   map[string]interface{}{"strategy": "mixed"},
+		},
+	}})
+
+	assert.Empty(t, Detect(expected, []string{"gpu-a"}, []fleetutil.BundleDeploymentState{overridden}))
+	findings := Detect(expected, []string{"gpu-b"}, []fleetutil.BundleDeploymentState{deployment("gpu-b", "v24.6.0")})
+	require.Len(t, findings, 1)
+	assert.Contains(t, findings[0].Message, "image.operatorTag")
+}
+
+func TestDetect_MissingDeployment(t *testing.T) {
+	findings := Detect(expectedNVIDIA(), []string{"gpu-a", "gpu-b"}, []fleetutil.BundleDeploymentState{
+		deployment("gpu-a", "v24.9.0"),
+	})
+
+	require.Len(t, findings, 1)
+	assert.Equal(t, "gpu-b", findings[0].Cluster)
+	assert.Equal(t, TypeMissingDeployment, findings[0].Type)
+}
+
+func TestDetect_ModifiedResources(t *testing.T) {
+	bd := deployment("gpu-a", "v24.9.0")
+	bd.NonModified = false
+	bd.State = "Modified"
+	bd.Modified = []string{"DaemonSet gpu-operator/nvidia-driver (modified)"}
+
+	stateOnly := deployment("gpu-b", "v24.9.0")
+	stateOnly.State = "Modified"
+
+	findings := Detect(expectedNVIDIA(), []string{"gpu-a", "gpu-b"}, []fleetutil.BundleDeploymentState{bd, stateOnly})
+
+	require.Len(t, findings, 2)
+	assert.Equal(t, TypeModifiedResources, findings[0].Type)
+	assert.Equal(t, "DaemonSet gpu-operator/nvidia-driver (modified)", findings[0].Message)
+	assert.Equal(t, "resources modified outside of Fleet", findings[1].Message)
+}
+
+func TestSummarize(t *testing.T) {
+	findings := []Finding{
+		{Cluster: "gpu-a", Type: TypeVersionMismatch},
+		{Cluster: "gpu-a", Type: TypeModifiedResources},
+		{Cluster: "gpu-c", Type: TypeMissingDeployment},
+	}
+
+	assert.Equal(t,
+		"2 of 3 clusters drifted: gpu-a (VersionMismatch), gpu-a (ModifiedResources), gpu-c (MissingDeployment)",
+		Summarize(findings, 3))
+}
+
+func TestSummarize_Truncates(t *testing.T) {
+	var findings []Finding
+	for i := 0; i < 12; i++ {
+		findings = append(findings, Finding{Cluster: fmt.Sprintf("gpu-%02d", i), Type: TypeMissingDeployment})
+	}
+
+	summary := Summarize(findings, 12)
+
+	assert.True(t, strings.HasPrefix(summary, "12 of 12 clusters drifted: gpu-00 (MissingDeployment)"))
+	assert.True(t, strings.HasSuffix(summary, "gpu-09 (MissingDeployment), and 2 more"))
+}
@@ -0,0 +1,105 @@
+package fleetutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// BundleTargets returns the targets of a Fleet Bundle
+func BundleTargets(bundle *unstructured.Unstructured) ([]Target, error) {
+	raw, _, err := unstructured.NestedSlice(bundle.Object, "spec", "targets")
+	if err != nil {
+		return nil, fmt.Errorf("invalid targets on Bundle %s/%s: %w", bundle.GetNamespace(), bundle.GetName(), err)
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	var targets []Target
+	if err := json.Unmarshal(data, &targets); err != nil {
+		return nil, fmt.Errorf("invalid targets on Bundle %s/%s: %w", bundle.GetNamespace(), bundle.GetName(), err)
+	}
+	return targets, nil
+}
+
+// MatchTarget returns the index of the target Fleet deploys a cluster with, the first one matching its
+// name and labels. ok is false when no target deploys the cluster.
+func MatchTarget(targets []Target, cluster string, clusterLabels map[string]string) (index int, ok bool, err error) {
+	for i, target := range targets {
+		if target.ClusterName == "" && target.ClusterSelector == nil {
+			continue
+		}
+		if target.ClusterName != "" && target.ClusterName != cluster {
+			continue
+		}
+		if target.ClusterSelector != nil {
+			selector, err := metav1.LabelSelectorAsSelector(target.ClusterSelector)
+			if err != nil {
+				return 0, false, fmt.Errorf("invalid cluster selector in target %d: %w", i, err)
+			}
+			if !selector.Matches(labels.Set(clusterLabels)) {
+				continue
+			}
+		}
+		if target.DoNotDeploy {
+			return 0, false, nil
+		}
+		return i, true, nil
+	}
+	return 0, false, nil
+}
+
+// BumpForceSyncGeneration increments the forceSyncGeneration of a Bundle target so the Fleet agents
+// redeploy the clusters it matches
+func BumpForceSyncGeneration(bundle *unstructured.Unstructured, index int) (int64, error) {
+	targets, _, err := unstructured.NestedSlice(bundle.Object, "spec", "targets")
+	if err != nil {
+		return 0, fmt.Errorf("invalid targets on Bundle %s/%s: %w", bundle.GetNamespace(), bundle.GetName(), err)
+	}
+	if index < 0 || index >= len(targets) {
+		return 0, fmt.Errorf("no target %d on Bundle %s/%s", index, bundle.GetNamespace(), bundle.GetName())
+	}
+	target, ok := targets[index].(map[string]interface{})
+	if !ok {
+		return 0, fmt.Errorf("invalid target %d on Bundle %s/%s", index, bundle.GetNamespace(), bundle.GetName())
+	}
+	generation, _, err := unstructured.NestedInt64(target, "forceSyncGeneration")
+	if err != nil {
+		return 0, fmt.Errorf("invalid forceSyncGeneration in target %d on Bundle %s/%s: %w", index, bundle.GetNamespace(), bundle.GetName(), err)
+	}
+	generation++
+	target["forceSyncGeneration"] = generation
+	if err := unstructured.SetNestedSlice(bundle.Object, targets, "spec", "targets"); err != nil {
+		return 0, err
+	}
+	return generation, nil
+}
+
+// KeepForceSyncGenerations returns targets with the forceSyncGeneration of the current targets matching
+// the same clusters, so that rewriting a Bundle neither loses a pending force-sync nor triggers one
+func KeepForceSyncGenerations(targets, current []Target) []Target {
+	out := make([]Target, len(targets))
+	for i, target := range targets {
+		out[i] = target
+		if target.BundleDeploymentOptions == nil {
+			continue
+		}
+		for _, c := range current {
+			if c.BundleDeploymentOptions == nil || c.ForceSyncGeneration == 0 ||
+				c.ClusterName != target.ClusterName || !reflect.DeepEqual(c.ClusterSelector, target.ClusterSelector) {
+				continue
+			}
+			// Targets may share their options
+			options := *target.BundleDeploymentOptions
+			options.ForceSyncGeneration = c.ForceSyncGeneration
+			out[i].BundleDeploymentOptions = &options
+			break
+		}
+	}
+	return out
+}
@@ -0,0 +1,110 @@
+package fleetutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func bundleWithTargets(t *testing.T, targets []Target) *unstructured.Unstructured {
+	raw, err := TargetsToUnstructured(targets)
+	require.NoError(t, err)
+	bundle := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	bundle.SetNamespace("cattle-fleet-system")
+	bundle.SetName("rmc-nvidia-stable")
+	require.NoError(t, unstructured.SetNestedSlice(bundle.Object, raw, "spec", "targets"))
+	return bundle
+}
+
+func TestBundleTargets(t *testing.T) {
+	options := &BundleDeploymentOptions{Helm: &HelmOptions{Chart: "gpu-operator"}}
+	targets := append(ExcludeClusters([]string{"gpu-a"}),
+		ConvertLabelSelectorToTargets(metav1.LabelSelector{MatchLabels: map[string]string{"gpu": "nvidia"}}, options)...)
+
+	parsed, err := BundleTargets(bundleWithTargets(t, targets))
+	require.NoError(t, err)
+	assert.Equal(t, targets, parsed)
+}
+
+func TestMatchTarget(t *testing.T) {
+	options := &BundleDeploymentOptions{Helm: &HelmOptions{Chart: "gpu-operator"}}
+	targets := append(ExcludeClusters([]string{"gpu-a"}),
+		Target{ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"stage": "canary"}}, BundleDeploymentOptions: options},
+		Target{ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"gpu": "nvidia"}}, BundleDeploymentOptions: options},
+	)
+
+	index, ok, err := MatchTarget(targets, "gpu-b", map[string]string{"gpu": "nvidia", "stage": "canary"})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 1, index)
+
+	index, ok, err = MatchTarget(targets, "gpu-c", map[string]string{"gpu": "nvidia"})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 2, index)
+
+	// Excluded and unmatched clusters are not deployed
+	_, ok, err = MatchTarget(targets, "gpu-a", map[string]string{"gpu": "nvidia"})
+	require.NoError(t, err)
+	assert.False(t, ok)
+	_, ok, err = MatchTarget(targets, "cpu-a", nil)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestBumpForceSyncGeneration(t *testing.T) {
+	options := &BundleDeploymentOptions{Helm: &HelmOptions{Chart: "gpu-operator"}}
+	bundle := bundleWithTargets(t, append(ExcludeClusters([]string{"gpu-a"}),
+		ConvertLabelSelectorToTargets(metav1.LabelSelector{}, options)...))
+
+	generation, err := BumpForceSyncGeneration(bundle, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), generation)
+
+	generation, err = BumpForceSyncGeneration(bundle, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), generation)
+
+	targets, err := BundleTargets(bundle)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), targets[1].ForceSyncGeneration)
+	assert.Nil(t, targets[0].BundleDeploymentOptions)
+
+	_, err = BumpForceSyncGeneration(bundle, 2)
+	assert.Error(t, err)
+}
+
+func TestBumpForceSyncGeneration_InvalidType(t *testing.T) {
+	bundle := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"targets": []interface{}{map[string]interface{}{"forceSyncGeneration": "one"}},
+		},
+	}}
+
+	_, err := BumpForceSyncGeneration(bundle, 0)
+	assert.Error(t, err)
+}
+
+func TestKeepForceSyncGenerations(t *testing.T) {
+	canary := metav1.LabelSelector{MatchLabels: map[string]string{"stage": "canary"}}
+	all := metav1.LabelSelector{}
+	current := []Target{
+		{ClusterSelector: &canary, BundleDeploymentOptions: &BundleDeploymentOptions{ForceSyncGeneration: 3}},
+		{ClusterSelector: &all, BundleDeploymentOptions: &BundleDeploymentOptions{}},
+	}
+
+	shared := &BundleDeploymentOptions{Helm: &HelmOptions{Version: "v24.9.0"}}
+	targets := []Target{
+		{ClusterSelector: &canary, BundleDeploymentOptions: shared},
+		{ClusterSelector: &all, BundleDeploymentOptions: shared},
+	}
+
+	kept := KeepForceSyncGenerations(targets, current)
+	assert.Equal(t, int64(3), kept[0].ForceSyncGeneration)
+	assert.Equal(t, "v24.9.0", kept[0].Helm.Version)
+	assert.Zero(t, kept[1].ForceSyncGeneration)
+	assert.Zero(t, shared.ForceSyncGeneration)
+}
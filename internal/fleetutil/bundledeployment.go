@@ -0,0 +1,104 @@
+package fleetutil
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Labels Fleet sets on every BundleDeployment
+const (
+	BundleNameLabel       = "fleet.cattle.io/bundle-name"
+	BundleNamespaceLabel  = "fleet.cattle.io/bundle-namespace"
+	ClusterLabel          = "fleet.cattle.io/cluster"
+	ClusterNamespaceLabel = "fleet.cattle.io/cluster-namespace"
+)
+
+// BundleDeploymentState is a typed view of the Fleet BundleDeployment fields we care about
+type BundleDeploymentState struct {
+	Name        string
+	Namespace   string
+	Bundle      string
+	Cluster     string
+	Ready       bool
+	NonModified bool
+	State       string
+	Message     string
+	// Modified lists resources Fleet reports as modified, missing or orphaned
+	Modified []string
+	Helm     HelmOptions
+}
+
+// ParseBundleDeployment extracts the deployment options and status of a Fleet BundleDeployment
+func ParseBundleDeployment(bd *unstructured.Unstructured) BundleDeploymentState {
+	labels := bd.GetLabels()
+	state := BundleDeploymentState{
+		Name:      bd.GetName(),
+		Namespace: bd.GetNamespace(),
+		Bundle:    labels[BundleNameLabel],
+		Cluster:   labels[ClusterLabel],
+	}
+	if state.Cluster == "" {
+		// BundleDeployments live in a per-cluster namespace
+		state.Cluster = bd.GetNamespace()
+	}
+
+	state.Ready, _, _ = unstructured.NestedBool(bd.Object, "status", "ready")
+	state.NonModified, _, _ = unstructured.NestedBool(bd.Object, "status", "nonModified")
+	state.State, _, _ = unstructured.NestedString(bd.Object, "status", "display", "state")
+	state.Message = conditionMessage(bd)
+
+	modified, _, _ := unstructured.NestedSlice(bd.Object, "status", "modifiedStatus")
+	for _, m := range modified {
+		res, ok := m.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		state.Modified = append(state.Modified, describeModified(res))
+	}
+
+	state.Helm.ReleaseName, _, _ = unstructured.NestedString(bd.Object, "spec", "options", "helm", "releaseName")
+	state.Helm.Repo, _, _ = unstructured.NestedString(bd.Object, "spec", "options", "helm", "repo")
+	state.Helm.Chart, _, _ = unstructured.NestedString(bd.Object, "spec", "options", "helm", "chart")
+	state.Helm.Version, _, _ = unstructured.NestedString(bd.Object, "spec", "options", "helm", "version")
+	state.Helm.Values, _, _ = unstructured.NestedMap(bd.Object, "spec", "options", "helm", "values")
+
+	return state
+}
+
+// conditionMessage returns the message of the first non-true condition, if any
+func conditionMessage(bd *unstructured.Unstructured) string {
+	conditions, _, _ := unstructured.NestedSlice(bd.Object, "status", "conditions")
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if status, _ := cond["status"].(string); status == "True" {
+			continue
+		}
+		if msg, _ := cond["message"].(string); msg != "" {
+			return msg
+		}
+	}
+	return ""
+}
+
+// describeModified renders a Fleet modifiedStatus entry as "<kind> <namespace>/<name> (<reason>)"
+func describeModified(res map[string]interface{}) string {
+	kind, _ := res["kind"].(string)
+	namespace, _ := res["namespace"].(string)
+	name, _ := res["name"].(string)
+
+	reason := "modified"
+	if missing, _ := res["missing"].(bool); missing {
+		reason = "missing"
+	} else if del, _ := res["delete"].(bool); del {
+		reason = "orphaned"
+	}
+
+	if namespace != "" {
+		name = namespace + "/" + name
+	}
+	return fmt.Sprintf("%s %s (%s)", kind, name, reason)
+}
@@ -0,0 +1,81 @@
+package fleetutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestParseBundleDeployment(t *testing.T) {
+	bd := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "fleet.cattle.io/v1alpha1",
+		"kind":       "BundleDeployment",
+		"metadata": map[string]interface{}{
+			"name":      "rmc-nvidia-stack",
+			"namespace": "cluster-fleet-default-gpu-a",
+			"labels": map[string]interface{}{
+				BundleNameLabel: "rmc-nvidia-stack",
+				ClusterLabel:    "gpu-a",
+			},
+		},
+		"spec": map[string]interface{}{
+			"options": map[string]interface{}{
+				"helm": map[string]interface{}{
+					"repo":  "https://nvidia.github.io/helm-charts",
+					"chart": "gpu-operator",
+					"values": map[string]interface{}{
+						"image": map[string]interface{}{
+							"operatorTag": "v24.9.0",
+						},
+					},
+				},
+			},
+		},
+		"status": map[string]interface{}{
+			"ready":       false,
+			"nonModified": false,
+			"display": map[string]interface{}{
+				"state": "Modified",
+			},
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Deployed", "status": "True", "message": ""},
+				map[string]interface{}{"type": "Ready", "status": "False", "message": "daemonset not ready"},
+			},
+			"modifiedStatus": []interface{}{
+				map[string]interface{}{"kind": "DaemonSet", "namespace": "gpu-operator", "name": "nvidia-driver", "patch": "{}"},
+				map[string]interface{}{"kind": "ConfigMap", "namespace": "gpu-operator", "name": "toolkit", "missing": true},
+				map[string]interface{}{"kind": "ClusterRole", "name": "stale", "delete": true},
+			},
+		},
+	}}
+
+	state := ParseBundleDeployment(bd)
+
+	assert.Equal(t, "rmc-nvidia-stack", state.Bundle)
+	assert.Equal(t, "gpu-a", state.Cluster)
+	assert.False(t, state.Ready)
+	assert.False(t, state.NonModified)
+	assert.Equal(t, "Modified", state.State)
+	assert.Equal(t, "daemonset not ready", state.Message)
+	assert.Equal(t, []string{
+		"DaemonSet gpu-operator/nvidia-driver (modified)",
+		"ConfigMap gpu-operator/toolkit (missing)",
+		"ClusterRole stale (orphaned)",
+	}, state.Modified)
+	assert.Equal(t, "gpu-operator", state.Helm.Chart)
+	assert.Equal(t, "https://nvidia.github.io/helm-charts", state.Helm.Repo)
+	assert.Equal(t, "v24.9.0", state.Helm.Values["image"].(map[string]interface{})["operatorTag"])
+}
+
+func TestParseBundleDeployment_ClusterFallsBackToNamespace(t *testing.T) {
+	bd := &unstructured.Unstructured{}
+	bd.SetName("rmc-amd-stack")
+	bd.SetNamespace("cluster-fleet-default-gpu-b")
+
+	state := ParseBundleDeployment(bd)
+
+	assert.Equal(t, "cluster-fleet-default-gpu-b", state.Cluster)
+	assert.Empty(t, state.Modified)
+	assert.Nil(t, state.Helm.Values)
+}
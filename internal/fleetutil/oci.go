@@ -0,0 +1,84 @@
+package fleetutil
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// OCIScheme prefixes chart references in OCI registries
+const OCIScheme = "oci://"
+
+var (
+	ociRegistry   = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9.-]*[a-zA-Z0-9])?(:[0-9]+)?$`)
+	ociRepository = regexp.MustCompile(`^[a-z0-9]+([._-][a-z0-9]+)*(/[a-z0-9]+([._-][a-z0-9]+)*)*$`)
+	ociTag        = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9._-]{0,127}$`)
+)
+
+// OCIReference is a chart stored in an OCI registry
+type OCIReference struct {
+	Registry   string
+	Repository string
+	// Tag is the chart version, empty for the latest
+	Tag string
+}
+
+// IsOCI reports whether a repository or chart reference points to an OCI registry
+func IsOCI(ref string) bool {
+	return strings.HasPrefix(ref, OCIScheme)
+}
+
+// ParseOCIReference parses and validates an oci://registry/repository[:tag] chart reference
+func ParseOCIReference(ref string) (OCIReference, error) {
+	if !IsOCI(ref) {
+		return OCIReference{}, fmt.Errorf("invalid OCI reference %q: missing %s scheme", ref, OCIScheme)
+	}
+	if strings.Contains(ref, "@") {
+		return OCIReference{}, fmt.Errorf("invalid OCI reference %q: digests are not supported, pin a tag", ref)
+	}
+
+	registry, repository, ok := strings.Cut(strings.TrimPrefix(ref, OCIScheme), "/")
+	if !ok || repository == "" {
+		return OCIReference{}, fmt.Errorf("invalid OCI reference %q: expected %sregistry/repository", ref, OCIScheme)
+	}
+	var tag string
+	if i := strings.LastIndex(repository, ":"); i >= 0 {
+		repository, tag = repository[:i], repository[i+1:]
+		if !ociTag.MatchString(tag) {
+			return OCIReference{}, fmt.Errorf("invalid OCI reference %q: invalid tag %q", ref, tag)
+		}
+	}
+	if !ociRegistry.MatchString(registry) {
+		return OCIReference{}, fmt.Errorf("invalid OCI reference %q: invalid registry %q", ref, registry)
+	}
+	if !ociRepository.MatchString(repository) {
+		return OCIReference{}, fmt.Errorf("invalid OCI reference %q: invalid repository %q", ref, repository)
+	}
+	return OCIReference{Registry: registry, Repository: repository, Tag: tag}, nil
+}
+
+// Chart returns the untagged oci:// reference Fleet expects as chart
+func (r OCIReference) Chart() string {
+	return OCIScheme + r.Registry + "/" + r.Repository
+}
+
+// ChartOptions returns the Helm options locating a chart. A chart of a Helm repository is
+// referenced by repo and chart name. A chart of an OCI repository (oci://registry/path) is
+// referenced by its full oci:// URL as chart, with the tag of chart, if any, as version.
+func ChartOptions(repo, chart string) (HelmOptions, error) {
+	if !IsOCI(repo) {
+		if repo == "" || chart == "" {
+			return HelmOptions{}, fmt.Errorf("chart repository and name are required")
+		}
+		return HelmOptions{Repo: repo, Chart: chart}, nil
+	}
+
+	if chart == "" {
+		return HelmOptions{}, fmt.Errorf("chart name is required for OCI repository %s", repo)
+	}
+	ref, err := ParseOCIReference(strings.TrimSuffix(repo, "/") + "/" + chart)
+	if err != nil {
+		return HelmOptions{}, err
+	}
+	return HelmOptions{Chart: ref.Chart(), Version: ref.Tag}, nil
+}
@@ -0,0 +1,53 @@
+package fleetutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOCIReference(t *testing.T) {
+	ref, err := ParseOCIReference("oci://registry.example.internal:5000/nvidia/gpu-operator:v24.9.0")
+	require.NoError(t, err)
+	assert.Equal(t, OCIReference{
+		Registry:   "registry.example.internal:5000",
+		Repository: "nvidia/gpu-operator",
+		Tag:        "v24.9.0",
+	}, ref)
+	assert.Equal(t, "oci://registry.example.internal:5000/nvidia/gpu-operator", ref.Chart())
+
+	ref, err = ParseOCIReference("oci://ghcr.io/rocm/device-plugin")
+	require.NoError(t, err)
+	assert.Empty(t, ref.Tag)
+}
+
+func TestParseOCIReference_Invalid(t *testing.T) {
+	for ref, msg := range map[string]string{
+		"https://nvidia.github.io/helm-charts":                      "missing oci:// scheme",
+		"oci://ghcr.io":                                             "expected oci://registry/repository",
+		"oci://ghcr.io/nvidia/GPU-Operator":                         "invalid repository",
+		"oci://ghcr.io/nvidia/gpu-operator:v1+1":                    "invalid tag",
+		"oci://ghcr_io/nvidia/gpu-operator":                         "invalid registry",
+		"oci://ghcr.io/nvidia/gpu-operator@sha256:0123456789abcdef": "digests are not supported",
+	} {
+		_, err := ParseOCIReference(ref)
+		assert.ErrorContains(t, err, msg, ref)
+	}
+}
+
+func TestChartOptions(t *testing.T) {
+	helm, err := ChartOptions("https://nvidia.github.io/helm-charts", "gpu-operator")
+	require.NoError(t, err)
+	assert.Equal(t, HelmOptions{Repo: "https://nvidia.github.io/helm-charts", Chart: "gpu-operator"}, helm)
+
+	helm, err = ChartOptions("oci://registry.example.internal/charts/", "gpu-operator:v24.9.0")
+	require.NoError(t, err)
+	assert.Equal(t, HelmOptions{Chart: "oci://registry.example.internal/charts/gpu-operator", Version: "v24.9.0"}, helm)
+
+	_, err = ChartOptions("oci://registry.example.internal/charts", "")
+	assert.ErrorContains(t, err, "chart name is required")
+
+	_, err = ChartOptions("", "gpu-operator")
+	assert.ErrorContains(t, err, "chart repository and name are required")
+}
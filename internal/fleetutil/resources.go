@@ -0,0 +1,41 @@
+package fleetutil
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// BundleResource is a manifest deployed as is by a Fleet Bundle
+type BundleResource struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+}
+
+// ResourcesFromObjects renders objects as Bundle resources, one YAML file per object named after
+// its kind and name
+func ResourcesFromObjects(objects []*unstructured.Unstructured) ([]BundleResource, error) {
+	resources := make([]BundleResource, 0, len(objects))
+	for _, obj := range objects {
+		content, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render %s %s: %w", obj.GetKind(), obj.GetName(), err)
+		}
+		resources = append(resources, BundleResource{
+			Name:    strings.ToLower(obj.GetKind()) + "-" + obj.GetName() + ".yaml",
+			Content: string(content),
+		})
+	}
+	return resources, nil
+}
+
+// ResourcesToUnstructured converts resources to the JSON-compatible form required by unstructured objects
+func ResourcesToUnstructured(resources []BundleResource) []interface{} {
+	out := make([]interface{}, 0, len(resources))
+	for _, r := range resources {
+		out = append(out, map[string]interface{}{"name": r.Name, "content": r.Content})
+	}
+	return out
+}
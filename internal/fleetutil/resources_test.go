@@ -0,0 +1,33 @@
+package fleetutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestResourcesFromObjects(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "kyverno.io/v1",
+		"kind":       "ClusterPolicy",
+		"metadata":   map[string]interface{}{"name": "rmc-global-config-limit-gpus-per-pod"},
+		"spec":       map[string]interface{}{"background": true},
+	}}
+
+	resources, err := ResourcesFromObjects([]*unstructured.Unstructured{obj})
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	assert.Equal(t, "clusterpolicy-rmc-global-config-limit-gpus-per-pod.yaml", resources[0].Name)
+
+	var decoded map[string]interface{}
+	require.NoError(t, yaml.Unmarshal([]byte(resources[0].Content), &decoded))
+	assert.Equal(t, obj.Object, decoded)
+
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"name":    resources[0].Name,
+		"content": resources[0].Content,
+	}}, ResourcesToUnstructured(resources))
+}
@@ -0,0 +1,80 @@
+package fleetutil
+
+import (
+	"sort"
+)
+
+// Fleet display states that indicate a failed deployment
+var failedStates = map[string]bool{
+	"ErrApplied": true,
+	"Modified":   true,
+	"NotReady":   true,
+}
+
+// Failed reports whether Fleet considers the deployment failed
+func (s BundleDeploymentState) Failed() bool {
+	return failedStates[s.State]
+}
+
+// RolloutSummary aggregates the BundleDeployments of a Bundle into per-cluster counts
+type RolloutSummary struct {
+	Desired int
+	Ready   int
+	Failed  int
+	Pending int
+	// Clusters holds one entry per deployment, failed first, then pending, then ready
+	Clusters []BundleDeploymentState
+}
+
+// SummarizeRollout counts ready, failed and pending clusters. desired is the number of clusters
+// Fleet targets; when unknown (0) the number of deployments is used.
+func SummarizeRollout(states []BundleDeploymentState, desired int) RolloutSummary {
+	summary := RolloutSummary{Desired: desired}
+	if summary.Desired < len(states) {
+		summary.Desired = len(states)
+	}
+
+	for _, s := range states {
+		switch {
+		case s.Failed():
+			summary.Failed++
+		case s.Ready:
+			summary.Ready++
+		}
+	}
+	summary.Pending = summary.Desired - summary.Ready - summary.Failed
+
+	summary.Clusters = append([]BundleDeploymentState(nil), states...)
+	sort.SliceStable(summary.Clusters, func(i, j int) bool {
+		ri, rj := rank(summary.Clusters[i]), rank(summary.Clusters[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return summary.Clusters[i].Cluster < summary.Clusters[j].Cluster
+	})
+	return summary
+}
+
+// Phase derives the Channel phase: Failed if any cluster failed, Completed once every
+// targeted cluster is ready, RollingOut otherwise
+func (s RolloutSummary) Phase() string {
+	switch {
+	case s.Failed > 0:
+		return "Failed"
+	case s.Desired > 0 && s.Ready == s.Desired:
+		return "Completed"
+	default:
+		return "RollingOut"
+	}
+}
+
+func rank(s BundleDeploymentState) int {
+	switch {
+	case s.Failed():
+		return 0
+	case !s.Ready:
+		return 1
+	default:
+		return 2
+	}
+}
@@ -0,0 +1,40 @@
+package fleetutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarizeRollout(t *testing.T) {
+	states := []BundleDeploymentState{
+		{Cluster: "gpu-c", Ready: true, State: "Ready"},
+		{Cluster: "gpu-a", Ready: true, State: "Ready"},
+		{Cluster: "gpu-b", State: "ErrApplied", Message: "helm install failed"},
+		{Cluster: "gpu-d", State: "WaitApplied"},
+	}
+
+	summary := SummarizeRollout(states, 5)
+
+	assert.Equal(t, 5, summary.Desired)
+	assert.Equal(t, 2, summary.Ready)
+	assert.Equal(t, 1, summary.Failed)
+	assert.Equal(t, 2, summary.Pending)
+	assert.Equal(t, "Failed", summary.Phase())
+
+	var order []string
+	for _, c := range summary.Clusters {
+		order = append(order, c.Cluster)
+	}
+	assert.Equal(t, []string{"gpu-b", "gpu-d", "gpu-a", "gpu-c"}, order)
+}
+
+func TestSummarizeRollout_Phase(t *testing.T) {
+	ready := BundleDeploymentState{Cluster: "gpu-a", Ready: true, State: "Ready"}
+	pending := BundleDeploymentState{Cluster: "gpu-b", State: "WaitApplied"}
+
+	assert.Equal(t, "RollingOut", SummarizeRollout(nil, 0).Phase())
+	assert.Equal(t, "RollingOut", SummarizeRollout([]BundleDeploymentState{ready, pending}, 0).Phase())
+	assert.Equal(t, "RollingOut", SummarizeRollout([]BundleDeploymentState{ready}, 2).Phase())
+	assert.Equal(t, "Completed", SummarizeRollout([]BundleDeploymentState{ready}, 0).Phase())
+}
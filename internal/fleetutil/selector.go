@@ -1,19 +1,30 @@
 package fleetutil
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-// Target represents a Fleet target
+// Target represents a Fleet target. Fleet inlines the deployment options into the target.
 type Target struct {
-	ClusterSelector         *metav1.LabelSelector    `json:"clusterSelector,omitempty"`
-	BundleDeploymentOptions *BundleDeploymentOptions `json:"bundleDeploymentOptions,omitempty"`
+	ClusterName     string                `json:"clusterName,omitempty"`
+	ClusterSelector *metav1.LabelSelector `json:"clusterSelector,omitempty"`
+	// DoNotDeploy keeps the Bundle off the matched clusters. Fleet uses the first matching target,
+	// so exclusions must precede the targets they carve clusters out of.
+	DoNotDeploy bool `json:"doNotDeploy,omitempty"`
+	*BundleDeploymentOptions
 }
 
 // BundleDeploymentOptions represents Fleet bundle deployment options
 type BundleDeploymentOptions struct {
 	DefaultNamespace string       `json:"defaultNamespace,omitempty"`
 	Helm             *HelmOptions `json:"helm,omitempty"`
+	// ForceSyncGeneration makes the Fleet agents redeploy the matched clusters whenever it changes
+	ForceSyncGeneration int64 `json:"forceSyncGeneration,omitempty"`
 }
 
 // HelmOptions represents Helm deployment options
@@ -21,9 +32,39 @@ type HelmOptions struct {
 	ReleaseName string                 `json:"releaseName,omitempty"`
 	Repo        string                 `json:"repo,omitempty"`
 	Chart       string                 `json:"chart,omitempty"`
+	Version     string                 `json:"version,omitempty"`
 	Values      map[string]interface{} `json:"values,omitempty"`
 }
 
+// HelmAppOptions configures how Fleet agents pull the Helm chart of a Bundle
+type HelmAppOptions struct {
+	// SecretName is a Secret in the Bundle namespace holding username, password and cacerts
+	SecretName            string `json:"helmAppSecretName,omitempty"`
+	InsecureSkipTLSVerify bool   `json:"helmAppInsecureSkipTLSVerify,omitempty"`
+}
+
+// ToUnstructured converts the options to the JSON-compatible form required by unstructured objects
+func (o HelmAppOptions) ToUnstructured() map[string]interface{} {
+	out := map[string]interface{}{}
+	if o.SecretName != "" {
+		out["helmAppSecretName"] = o.SecretName
+	}
+	if o.InsecureSkipTLSVerify {
+		out["helmAppInsecureSkipTLSVerify"] = true
+	}
+	return out
+}
+
+// HashValues returns a short, stable hash of Helm values. Map keys are serialized in sorted order.
+func HashValues(values map[string]interface{}) (string, error) {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:16], nil
+}
+
 // ConvertLabelSelectorToTargets converts a LabelSelector to Fleet targets
 func ConvertLabelSelectorToTargets(selector metav1.LabelSelector, options *BundleDeploymentOptions) []Target {
 	return []Target{
@@ -33,3 +74,47 @@ func ConvertLabelSelectorToTargets(selector metav1.LabelSelector, options *Bundl
 		},
 	}
 }
+
+// ExcludeClusters returns the targets keeping a Bundle off the named clusters
+func ExcludeClusters(names []string) []Target {
+	targets := make([]Target, 0, len(names))
+	for _, name := range names {
+		targets = append(targets, Target{ClusterName: name, DoNotDeploy: true})
+	}
+	return targets
+}
+
+// MergeSelectors returns a selector matching only clusters matched by both a and b.
+// matchLabels are rewritten as In expressions so conflicting keys cannot overwrite each other.
+func MergeSelectors(a, b metav1.LabelSelector) metav1.LabelSelector {
+	var merged metav1.LabelSelector
+	for _, s := range []metav1.LabelSelector{a, b} {
+		keys := make([]string, 0, len(s.MatchLabels))
+		for k := range s.MatchLabels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			merged.MatchExpressions = append(merged.MatchExpressions, metav1.LabelSelectorRequirement{
+				Key:      k,
+				Operator: metav1.LabelSelectorOpIn,
+				Values:   []string{s.MatchLabels[k]},
+			})
+		}
+		merged.MatchExpressions = append(merged.MatchExpressions, s.MatchExpressions...)
+	}
+	return merged
+}
+
+// TargetsToUnstructured converts targets to the JSON-compatible form required by unstructured objects
+func TargetsToUnstructured(targets []Target) ([]interface{}, error) {
+	data, err := json.Marshal(targets)
+	if err != nil {
+		return nil, err
+	}
+	var out []interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 func TestConvertLabelSelectorToTargets(t *testing.T) {
@@ -94,3 +95,98 @@ func TestConvertLabelSelectorToTargets_EmptySelector(t *testing.T) {
 	assert.Empty(t, target.ClusterSelector.MatchLabels)
 	assert.Empty(t, target.ClusterSelector.MatchExpressions)
 }
+
+func TestMergeSelectors(t *testing.T) {
+	channel := metav1.LabelSelector{
+		MatchLabels: map[string]string{"gpu": "nvidia"},
+	}
+	wave := metav1.LabelSelector{
+		MatchLabels: map[string]string{"env": "staging"},
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "region", Operator: metav1.LabelSelectorOpIn, Values: []string{"eu"}},
+		},
+	}
+
+	merged := MergeSelectors(channel, wave)
+
+	assert.Empty(t, merged.MatchLabels)
+	assert.Equal(t, []metav1.LabelSelectorRequirement{
+		{Key: "gpu", Operator: metav1.LabelSelectorOpIn, Values: []string{"nvidia"}},
+		{Key: "env", Operator: metav1.LabelSelectorOpIn, Values: []string{"staging"}},
+		{Key: "region", Operator: metav1.LabelSelectorOpIn, Values: []string{"eu"}},
+	}, merged.MatchExpressions)
+
+	selector, err := metav1.LabelSelectorAsSelector(&merged)
+	assert.NoError(t, err)
+	assert.True(t, selector.Matches(labels.Set{"gpu": "nvidia", "env": "staging", "region": "eu"}))
+	assert.False(t, selector.Matches(labels.Set{"gpu": "nvidia", "env": "prod", "region": "eu"}))
+}
+
+func TestTargetsToUnstructured(t *testing.T) {
+	targets := []Target{
+		{
+			ClusterName: "gpu-a",
+			BundleDeploymentOptions: &BundleDeploymentOptions{
+				DefaultNamespace: "gpu-operator",
+				Helm: &HelmOptions{
+					Chart:  "gpu-operator",
+					Values: map[string]interface{}{"replicas": 2},
+				},
+			},
+		},
+	}
+
+	out, err := TargetsToUnstructured(targets)
+	assert.NoError(t, err)
+	assert.Len(t, out, 1)
+
+	// Deployment options are inlined into the target as Fleet expects
+	target := out[0].(map[string]interface{})
+	assert.Equal(t, "gpu-a", target["clusterName"])
+	assert.Equal(t, "gpu-operator", target["defaultNamespace"])
+	assert.NotContains(t, target, "bundleDeploymentOptions")
+	helm := target["helm"].(map[string]interface{})
+	assert.Equal(t, float64(2), helm["values"].(map[string]interface{})["replicas"])
+}
+
+func TestHashValues(t *testing.T) {
+	a, err := HashValues(map[string]interface{}{
+		"image": map[string]interface{}{"operatorTag": "v24.9.0", "runtimeTag": "12.4.1"},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, a, 16)
+
+	b, err := HashValues(map[string]interface{}{
+		"image": map[string]interface{}{"runtimeTag": "12.4.1", "operatorTag": "v24.9.0"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, a, b, "key order must not matter")
+
+	c, err := HashValues(map[string]interface{}{
+		"image": map[string]interface{}{"operatorTag": "v25.0.0", "runtimeTag": "12.4.1"},
+	})
+	assert.NoError(t, err)
+	assert.NotEqual(t, a, c)
+}
+
+func TestExcludeClusters(t *testing.T) {
+	options := &BundleDeploymentOptions{DefaultNamespace: "gpu-operator"}
+	selector := metav1.LabelSelector{MatchLabels: map[string]string{"gpu": "nvidia"}}
+	targets := append(ExcludeClusters([]string{"gpu-a"}), ConvertLabelSelectorToTargets(selector, options)...)
+
+	out, err := TargetsToUnstructured(targets)
+	assert.NoError(t, err)
+	assert.Len(t, out, 2)
+	assert.Equal(t, map[string]interface{}{"clusterName": "gpu-a", "doNotDeploy": true}, out[0])
+	assert.Equal(t, "gpu-operator", out[1].(map[string]interface{})["defaultNamespace"])
+	assert.NotContains(t, out[1], "doNotDeploy")
+}
+
+func TestHelmAppOptions_ToUnstructured(t *testing.T) {
+	assert.Equal(t, map[string]interface{}{
+		"helmAppSecretName":            "rmc-nvidia-stable-helm",
+		"helmAppInsecureSkipTLSVerify": true,
+	}, HelmAppOptions{SecretName: "rmc-nvidia-stable-helm", InsecureSkipTLSVerify: true}.ToUnstructured())
+
+	assert.Equal(t, map[string]interface{}{"helmAppSecretName": "creds"}, HelmAppOptions{SecretName: "creds"}.ToUnstructured())
+}
@@ -0,0 +1,64 @@
+package fleetutil
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ValuesOverride merges Helm values on the clusters matched by a selector
+type ValuesOverride struct {
+	ClusterSelector metav1.LabelSelector   `json:"clusterSelector"`
+	Values          map[string]interface{} `json:"values"`
+}
+
+// MergeValues returns base deep-merged with overlay. Nested maps are merged key by key, any other
+// overlay value, lists included, replaces the base value. Neither argument is modified.
+func MergeValues(base, overlay map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(base)+len(overlay))
+	for k, v := range base {
+		out[k] = v
+	}
+	for k, v := range overlay {
+		if src, ok := v.(map[string]interface{}); ok {
+			if dst, ok := out[k].(map[string]interface{}); ok {
+				out[k] = MergeValues(dst, src)
+				continue
+			}
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// ApplyValuesOverrides precedes every deploying target with one target per override, narrowed to the
+// override's clusters and deploying its values merged over the target's. Fleet uses the first
+// matching target, so a cluster matched by several overrides only gets the first one.
+func ApplyValuesOverrides(targets []Target, overrides []ValuesOverride) []Target {
+	if len(overrides) == 0 {
+		return targets
+	}
+
+	out := make([]Target, 0, len(targets)*(len(overrides)+1))
+	for _, target := range targets {
+		if target.DoNotDeploy || target.BundleDeploymentOptions == nil || target.Helm == nil {
+			out = append(out, target)
+			continue
+		}
+		for _, override := range overrides {
+			selector := override.ClusterSelector
+			if target.ClusterSelector != nil {
+				selector = MergeSelectors(*target.ClusterSelector, selector)
+			}
+			helm := *target.Helm
+			helm.Values = MergeValues(helm.Values, override.Values)
+			options := *target.BundleDeploymentOptions
+			options.Helm = &helm
+			out = append(out, Target{
+				ClusterName:             target.ClusterName,
+				ClusterSelector:         &selector,
+				BundleDeploymentOptions: &options,
+			})
+		}
+		out = append(out, target)
+	}
+	return out
+}
@@ -0,0 +1,76 @@
+package fleetutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestMergeValues(t *testing.T) {
+	base := map[string]interface{}{
+		"operator":    map[string]interface{}{"version": "v24.9.0"},
+		"driver":      map[string]interface{}{"version": "550.127", "enabled": true},
+		"tolerations": []interface{}{"a"},
+	}
+	overlay := map[string]interface{}{
+		"driver":      map[string]interface{}{"enabled": false},
+		"mig":         map[string]interface{}{"strategy": "mixed"},
+		"tolerations": []interface{}{"b"},
+	}
+
+	merged := MergeValues(base, overlay)
+	assert.Equal(t, map[string]interface{}{
+		"operator":    map[string]interface{}{"version": "v24.9.0"},
+		"driver":      map[string]interface{}{"version": "550.127", "enabled": false},
+		"mig":         map[string]interface{}{"strategy": "mixed"},
+		"tolerations": []interface{}{"b"},
+	}, merged)
+
+	// Inputs are left untouched
+	assert.Equal(t, true, base["driver"].(map[string]interface{})["enabled"])
+	assert.Equal(t, map[string]interface{}{"version": "v24.9.0"}, MergeValues(nil, map[string]interface{}{"version": "v24.9.0"}))
+}
+
+func TestApplyValuesOverrides(t *testing.T) {
+	selector := metav1.LabelSelector{MatchLabels: map[string]string{"gpu": "nvidia"}}
+	options := &BundleDeploymentOptions{
+		DefaultNamespace: "gpu-operator",
+		Helm: &HelmOptions{
+			Chart:  "gpu-operator",
+			Values: map[string]interface{}{"driver": map[string]interface{}{"enabled": true}},
+		},
+	}
+	targets := append(ExcludeClusters([]string{"edge-1"}), Target{ClusterName: "lab-1", BundleDeploymentOptions: options})
+	targets = append(targets, ConvertLabelSelectorToTargets(selector, options)...)
+
+	overrides := []ValuesOverride{{
+		ClusterSelector: metav1.LabelSelector{MatchLabels: map[string]string{"driver": "preinstalled"}},
+		Values:          map[string]interface{}{"driver": map[string]interface{}{"enabled": false}},
+	}}
+	out := ApplyValuesOverrides(targets, overrides)
+
+	assert.Len(t, out, 5)
+	assert.True(t, out[0].DoNotDeploy, "exclusions are kept first")
+
+	// Named cluster: the override narrows the cluster with its selector
+	assert.Equal(t, "lab-1", out[1].ClusterName)
+	assert.Equal(t, overrides[0].ClusterSelector, *out[1].ClusterSelector)
+	assert.Equal(t, false, out[1].Helm.Values["driver"].(map[string]interface{})["enabled"])
+	assert.Equal(t, targets[1], out[2])
+
+	// Selector target: the override only matches the clusters of both selectors
+	merged, err := metav1.LabelSelectorAsSelector(out[3].ClusterSelector)
+	assert.NoError(t, err)
+	assert.True(t, merged.Matches(labels.Set{"gpu": "nvidia", "driver": "preinstalled"}))
+	assert.False(t, merged.Matches(labels.Set{"driver": "preinstalled"}))
+	assert.Equal(t, "gpu-operator", out[3].DefaultNamespace)
+	assert.Equal(t, false, out[3].Helm.Values["driver"].(map[string]interface{})["enabled"])
+	assert.Equal(t, targets[2], out[4])
+
+	// The base options are shared, not modified
+	assert.Equal(t, true, options.Helm.Values["driver"].(map[string]interface{})["enabled"])
+
+	assert.Equal(t, targets, ApplyValuesOverrides(targets, nil))
+}
@@ -0,0 +1,154 @@
+package helmvalues
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	multisuseiov1alpha1 "github.com/suse/rancher-multi-compute/api/multi.suse.io/v1alpha1"
+	"github.com/suse/rancher-multi-compute/internal/fleetutil"
+)
+
+// DefaultKey is the key values are read from when a reference sets none
+const DefaultKey = "values.yaml"
+
+// Values are the Helm values a Channel sets on top of the values generated from its pins
+type Values struct {
+	// Base is merged over the pin values on every cluster, from valuesFrom then values
+	Base map[string]interface{}
+	// Overrides are merged over the channel values on the clusters they select
+	Overrides []fleetutil.ValuesOverride
+}
+
+// Load reads the Helm values of a Channel, with valuesFrom references looked up in namespace
+func Load(ctx context.Context, reader client.Reader, namespace string, spec *multisuseiov1alpha1.ChannelSpec) (Values, error) {
+	var values Values
+	for _, ref := range spec.ValuesFrom {
+		data, err := read(ctx, reader, namespace, ref)
+		if err != nil {
+			return Values{}, err
+		}
+		values.Base = fleetutil.MergeValues(values.Base, data)
+	}
+
+	if spec.Values != nil {
+		inline, err := decodeJSON(spec.Values.Raw)
+		if err != nil {
+			return Values{}, fmt.Errorf("invalid values: %w", err)
+		}
+		values.Base = fleetutil.MergeValues(values.Base, inline)
+	}
+
+	for i, override := range spec.ValuesOverrides {
+		if _, err := metav1.LabelSelectorAsSelector(&override.ClusterSelector); err != nil {
+			return Values{}, fmt.Errorf("invalid cluster selector of values override %d: %w", i, err)
+		}
+		data, err := decodeJSON(override.Values.Raw)
+		if err != nil {
+			return Values{}, fmt.Errorf("invalid values of values override %d: %w", i, err)
+		}
+		values.Overrides = append(values.Overrides, fleetutil.ValuesOverride{
+			ClusterSelector: override.ClusterSelector,
+			Values:          data,
+		})
+	}
+	return values, nil
+}
+
+// Apply returns the pin values merged with the channel values of every cluster
+func (v Values) Apply(pinValues map[string]interface{}) map[string]interface{} {
+	return fleetutil.MergeValues(pinValues, v.Base)
+}
+
+// Hash returns a short, stable hash of the values deployed from the pin values, overrides included
+func (v Values) Hash(pinValues map[string]interface{}) (string, error) {
+	values := v.Apply(pinValues)
+	if len(v.Overrides) == 0 {
+		// Keeps the hash of channels without overrides stable
+		return fleetutil.HashValues(values)
+	}
+	return fleetutil.HashValues(map[string]interface{}{
+		"values":    values,
+		"overrides": v.Overrides,
+	})
+}
+
+// read returns the values stored in the key of a referenced ConfigMap or Secret
+func read(ctx context.Context, reader client.Reader, namespace string, ref multisuseiov1alpha1.ValuesReference) (map[string]interface{}, error) {
+	key := ref.Key
+	if key == "" {
+		key = DefaultKey
+	}
+	name := types.NamespacedName{Namespace: namespace, Name: ref.Name}
+
+	var (
+		data  []byte
+		found bool
+		err   error
+	)
+	switch ref.Kind {
+	case multisuseiov1alpha1.ValuesKindConfigMap:
+		cm := &corev1.ConfigMap{}
+		if err = reader.Get(ctx, name, cm); err == nil {
+			var value string
+			value, found = cm.Data[key]
+			data = []byte(value)
+		}
+	case multisuseiov1alpha1.ValuesKindSecret:
+		secret := &corev1.Secret{}
+		if err = reader.Get(ctx, name, secret); err == nil {
+			data, found = secret.Data[key]
+		}
+	default:
+		return nil, fmt.Errorf("unsupported values kind %q", ref.Kind)
+	}
+
+	switch {
+	case errors.IsNotFound(err) && ref.Optional:
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to get values %s %s: %w", ref.Kind, name, err)
+	case !found && ref.Optional:
+		return nil, nil
+	case !found:
+		return nil, fmt.Errorf("values %s %s has no %s key", ref.Kind, name, key)
+	}
+
+	values, err := decodeYAML(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid values in key %s of %s %s: %w", key, ref.Kind, name, err)
+	}
+	return values, nil
+}
+
+// decodeYAML decodes YAML values into the JSON-compatible form of the values from the Channel spec
+func decodeYAML(data []byte) (map[string]interface{}, error) {
+	var values map[string]interface{}
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("values must be a map with string keys: %w", err)
+	}
+	return decodeJSON(raw)
+}
+
+// decodeJSON decodes JSON values, null decoding to no values
+func decodeJSON(raw []byte) (map[string]interface{}, error) {
+	var values map[string]interface{}
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return nil, fmt.Errorf("values must be a map: %w", err)
+	}
+	return values, nil
+}
@@ -0,0 +1,115 @@
+package helmvalues
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	multisuseiov1alpha1 "github.com/suse/rancher-multi-compute/api/multi.suse.io/v1alpha1"
+	"github.com/suse/rancher-multi-compute/internal/fleetutil"
+)
+
+const namespace = "cattle-fleet-system"
+
+func TestLoad(t *testing.T) {
+	reader := fake.NewClientBuilder().WithObjects(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "gpu-defaults", Namespace: namespace},
+			Data: map[string]string{DefaultKey: `
+mig:
+  strategy: single
+driver:
+  enabled: true
+  rdma: false
+`},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "gpu-secrets", Namespace: namespace},
+			Data:       map[string][]byte{"registry.yaml": []byte("driver:\n  repository: registry.example.com\n")},
+		},
+	).Build()
+
+	spec := &multisuseiov1alpha1.ChannelSpec{
+		ValuesFrom: []multisuseiov1alpha1.ValuesReference{
+			{Kind: multisuseiov1alpha1.ValuesKindConfigMap, Name: "gpu-defaults"},
+			{Kind: multisuseiov1alpha1.ValuesKindSecret, Name: "gpu-secrets", Key: "registry.yaml"},
+			{Kind: multisuseiov1alpha1.ValuesKindConfigMap, Name: "missing", Optional: true},
+		},
+		Values: &apiextensionsv1.JSON{Raw: []byte(`{"driver":{"rdma":true}}`)},
+		ValuesOverrides: []multisuseiov1alpha1.ValuesOverride{{
+			ClusterSelector: metav1.LabelSelector{MatchLabels: map[string]string{"driver": "preinstalled"}},
+			Values:          apiextensionsv1.JSON{Raw: []byte(`{"driver":{"enabled":false}}`)},
+		}},
+	}
+
+	values, err := Load(context.Background(), reader, namespace, spec)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"mig": map[string]interface{}{"strategy": "single"},
+		"driver": map[string]interface{}{
+			"enabled":    true,
+			"rdma":       true,
+			"repository": "registry.example.com",
+		},
+	}, values.Base)
+	require.Len(t, values.Overrides, 1)
+	assert.Equal(t, map[string]interface{}{"driver": map[string]interface{}{"enabled": false}}, values.Overrides[0].Values)
+
+	// Channel values are merged over the pin values
+	applied := values.Apply(map[string]interface{}{"driver": map[string]interface{}{"version": "550.127"}})
+	assert.Equal(t, "550.127", applied["driver"].(map[string]interface{})["version"])
+	assert.Equal(t, true, applied["driver"].(map[string]interface{})["rdma"])
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	reader := fake.NewClientBuilder().WithObjects(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "list", Namespace: namespace},
+			Data:       map[string]string{DefaultKey: "- a\n- b\n"},
+		},
+	).Build()
+
+	cases := map[string]multisuseiov1alpha1.ChannelSpec{
+		"missing object": {ValuesFrom: []multisuseiov1alpha1.ValuesReference{
+			{Kind: multisuseiov1alpha1.ValuesKindSecret, Name: "missing"},
+		}},
+		"missing key": {ValuesFrom: []multisuseiov1alpha1.ValuesReference{
+			{Kind: multisuseiov1alpha1.ValuesKindConfigMap, Name: "list", Key: "other.yaml"},
+		}},
+		"not a map": {ValuesFrom: []multisuseiov1alpha1.ValuesReference{
+			{Kind: multisuseiov1alpha1.ValuesKindConfigMap, Name: "list"},
+		}},
+		"inline not a map": {Values: &apiextensionsv1.JSON{Raw: []byte(`["a"]`)}},
+		"invalid override selector": {ValuesOverrides: []multisuseiov1alpha1.ValuesOverride{{
+			ClusterSelector: metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "gpu", Operator: "Unknown"},
+			}},
+		}}},
+	}
+	for name, spec := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := Load(context.Background(), reader, namespace, &spec)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestValuesHash(t *testing.T) {
+	pins := map[string]interface{}{"operator": map[string]interface{}{"version": "v24.9.0"}}
+
+	plain, err := Values{}.Hash(pins)
+	require.NoError(t, err)
+	base, err := Values{Base: map[string]interface{}{"mig": map[string]interface{}{"strategy": "mixed"}}}.Hash(pins)
+	require.NoError(t, err)
+	overridden, err := Values{Overrides: []fleetutil.ValuesOverride{{Values: map[string]interface{}{"x": 1}}}}.Hash(pins)
+	require.NoError(t, err)
+
+	assert.NotEqual(t, plain, base)
+	assert.NotEqual(t, plain, overridden)
+}
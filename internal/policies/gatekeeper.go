@@ -0,0 +1,293 @@
+package policies
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	multisuseiov1alpha1 "github.com/suse/rancher-multi-compute/api/multi.suse.io/v1alpha1"
+)
+
+// ConstraintTemplateGVK is the kind of the Gatekeeper constraint templates
+var ConstraintTemplateGVK = schema.GroupVersionKind{
+	Group:   "templates.gatekeeper.sh",
+	Version: "v1",
+	Kind:    "ConstraintTemplate",
+}
+
+// gatekeeperTemplate is the ConstraintTemplate defining the constraint kind of a policy
+type gatekeeperTemplate struct {
+	kind       string
+	parameters map[string]interface{}
+	rego       string
+}
+
+// regoContainers collects the containers of the reviewed pod
+const regoContainers = `
+input_containers[c] {
+  c := input.review.object.spec.containers[_]
+}
+
+input_containers[c] {
+  c := input.review.object.spec.initContainers[_]
+}
+`
+
+// gatekeeperTemplates are the constraint templates, by policy
+var gatekeeperTemplates = map[string]gatekeeperTemplate{
+	LimitGPUsPerPod: {
+		kind: "K8sGPULimit",
+		parameters: map[string]interface{}{
+			"limit":     map[string]interface{}{"type": "integer"},
+			"resources": stringArraySchema(),
+		},
+		rego: `package k8sgpulimit
+
+violation[{"msg": msg}] {
+  resource := input.parameters.resources[_]
+  requested := pod_gpus(resource)
+  requested > input.parameters.limit
+  msg := sprintf("pod requests %v %v, more than the limit of %v", [requested, resource, input.parameters.limit])
+}
+
+# Containers run together while init containers run one at a time before them
+pod_gpus(resource) = requested {
+  containers := sum([container_gpus(c, resource) | c := input.review.object.spec.containers[_]])
+  init := [container_gpus(c, resource) | c := input.review.object.spec.initContainers[_]]
+  requested := max(array.concat([containers], init))
+}
+
+# Kubernetes requires extended resources to be limited, and requests to match the limits
+container_gpus(container, resource) = to_number(container.resources.limits[resource])
+
+container_gpus(container, resource) = 0 {
+  not container.resources.limits[resource]
+}
+`,
+	},
+	RestrictGPUNamespaces: {
+		kind: "K8sGPUNamespaces",
+		parameters: map[string]interface{}{
+			"resources":        stringArraySchema(),
+			"resourcePrefixes": stringArraySchema(),
+		},
+		rego: `package k8sgpunamespaces
+
+violation[{"msg": msg}] {
+  container := input_containers[_]
+  field := ["requests", "limits"][_]
+  container.resources[field][resource]
+  restricted_resource(resource)
+  msg := sprintf("container %v requests %v in namespace %v, which does not allow GPU workloads", [container.name, resource, input.review.object.metadata.namespace])
+}
+
+restricted_resource(name) {
+  name == input.parameters.resources[_]
+}
+
+restricted_resource(name) {
+  startswith(name, input.parameters.resourcePrefixes[_])
+}
+` + regoContainers,
+	},
+	EnforceRuntimeClass: {
+		kind: "K8sRequiredRuntimeClass",
+		parameters: map[string]interface{}{
+			"resources": map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"name":         map[string]interface{}{"type": "string"},
+						"runtimeClass": map[string]interface{}{"type": "string"},
+					},
+				},
+			},
+		},
+		rego: `package k8srequiredruntimeclass
+
+violation[{"msg": msg}] {
+  resource := input.parameters.resources[_]
+  requests_resource(resource.name)
+  class := object.get(resource, "runtimeClass", "")
+  class != ""
+  object.get(input.review.object.spec, "runtimeClassName", "") != class
+  msg := sprintf("pods requesting %v must use the %v RuntimeClass", [resource.name, class])
+}
+
+violation[{"msg": msg}] {
+  resource := input.parameters.resources[_]
+  requests_resource(resource.name)
+  object.get(resource, "runtimeClass", "") == ""
+  object.get(input.review.object.spec, "runtimeClassName", "") == ""
+  msg := sprintf("pods requesting %v must set a RuntimeClass", [resource.name])
+}
+
+requests_resource(name) {
+  input_containers[_].resources.limits[name]
+}
+` + regoContainers,
+	},
+}
+
+// GatekeeperConstraintGVK returns the kind of the Gatekeeper constraints enforcing a policy
+func GatekeeperConstraintGVK(policy string) (schema.GroupVersionKind, error) {
+	template, ok := gatekeeperTemplates[policy]
+	if !ok {
+		return schema.GroupVersionKind{}, fmt.Errorf("unknown policy %q", policy)
+	}
+	return schema.GroupVersionKind{
+		Group:   "constraints.gatekeeper.sh",
+		Version: "v1beta1",
+		Kind:    template.kind,
+	}, nil
+}
+
+// Gatekeeper renders the ConstraintTemplate of a policy of config and its constraint, named name.
+// Templates are named after their constraint kind, so they are shared by every constraint of a policy.
+func Gatekeeper(policy, name string, config multisuseiov1alpha1.PolicyConfig) (template, constraint *unstructured.Unstructured, err error) {
+	gvk, err := GatekeeperConstraintGVK(policy)
+	if err != nil {
+		return nil, nil, err
+	}
+	definition := gatekeeperTemplates[policy]
+
+	template = &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"crd": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"names": map[string]interface{}{"kind": definition.kind},
+					"validation": map[string]interface{}{
+						"openAPIV3Schema": map[string]interface{}{
+							"type":       "object",
+							"properties": definition.parameters,
+						},
+					},
+				},
+			},
+			"targets": []interface{}{map[string]interface{}{
+				"target": "admission.k8s.gatekeeper.sh",
+				"rego":   definition.rego,
+			}},
+		},
+	}}
+	template.SetGroupVersionKind(ConstraintTemplateGVK)
+	template.SetName(strings.ToLower(definition.kind))
+
+	match := map[string]interface{}{
+		"kinds": []interface{}{map[string]interface{}{
+			"apiGroups": []interface{}{""},
+			"kinds":     []interface{}{"Pod"},
+		}},
+	}
+	var parameters map[string]interface{}
+	switch policy {
+	case LimitGPUsPerPod:
+		parameters = map[string]interface{}{
+			"limit":     int64(config.LimitGPUsPerPod),
+			"resources": resourceNames(),
+		}
+	case RestrictGPUNamespaces:
+		prefixes := make([]interface{}, 0, len(GPUResourcePrefixes))
+		for _, prefix := range GPUResourcePrefixes {
+			prefixes = append(prefixes, prefix)
+		}
+		parameters = map[string]interface{}{
+			"resources":        resourceNames(),
+			"resourcePrefixes": prefixes,
+		}
+		if len(config.AllowedNamespaces) > 0 {
+			excluded := make([]interface{}, 0, len(config.AllowedNamespaces))
+			for _, ns := range config.AllowedNamespaces {
+				excluded = append(excluded, ns)
+			}
+			match["excludedNamespaces"] = excluded
+		}
+		if selector := NamespaceSelector(config); selector != nil {
+			negated, err := negateSelector(selector)
+			if err != nil {
+				return nil, nil, err
+			}
+			match["namespaceSelector"] = negated
+		}
+	case EnforceRuntimeClass:
+		classes := RuntimeClasses(config)
+		resources := make([]interface{}, 0, len(GPUResources))
+		for _, r := range GPUResources {
+			resource := map[string]interface{}{"name": r.Name}
+			if class, ok := classes[r.Vendor]; ok {
+				resource["runtimeClass"] = class
+			}
+			resources = append(resources, resource)
+		}
+		parameters = map[string]interface{}{"resources": resources}
+	}
+
+	action := "deny"
+	if config.Audit {
+		action = "warn"
+	}
+	constraint = &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"enforcementAction": action,
+			"match":             match,
+			"parameters":        parameters,
+		},
+	}}
+	constraint.SetGroupVersionKind(gvk)
+	constraint.SetName(name)
+	return template, constraint, nil
+}
+
+// negateSelector returns a selector matching the namespaces selector does not. Gatekeeper constraints
+// only match namespaces positively, so the allowed namespaces must be selected by a single requirement.
+func negateSelector(selector *metav1.LabelSelector) (map[string]interface{}, error) {
+	requirements := append([]metav1.LabelSelectorRequirement(nil), selector.MatchExpressions...)
+	for k, v := range selector.MatchLabels {
+		requirements = append(requirements, metav1.LabelSelectorRequirement{
+			Key:      k,
+			Operator: metav1.LabelSelectorOpIn,
+			Values:   []string{v},
+		})
+	}
+	if len(requirements) != 1 {
+		return nil, fmt.Errorf("the gatekeeper engine requires a namespace selector with a single requirement, got %d", len(requirements))
+	}
+
+	negated := requirements[0]
+	switch negated.Operator {
+	case metav1.LabelSelectorOpIn:
+		negated.Operator = metav1.LabelSelectorOpNotIn
+	case metav1.LabelSelectorOpNotIn:
+		negated.Operator = metav1.LabelSelectorOpIn
+	case metav1.LabelSelectorOpExists:
+		negated.Operator = metav1.LabelSelectorOpDoesNotExist
+	case metav1.LabelSelectorOpDoesNotExist:
+		negated.Operator = metav1.LabelSelectorOpExists
+	default:
+		return nil, fmt.Errorf("invalid namespace selector operator %q", negated.Operator)
+	}
+	return runtime.DefaultUnstructuredConverter.ToUnstructured(&metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{negated},
+	})
+}
+
+// resourceNames returns the names of GPUResources
+func resourceNames() []interface{} {
+	names := make([]interface{}, 0, len(GPUResources))
+	for _, r := range GPUResources {
+		names = append(names, r.Name)
+	}
+	return names
+}
+
+func stringArraySchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type":  "array",
+		"items": map[string]interface{}{"type": "string"},
+	}
+}
@@ -0,0 +1,146 @@
+package policies
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	multisuseiov1alpha1 "github.com/suse/rancher-multi-compute/api/multi.suse.io/v1alpha1"
+)
+
+func TestGatekeeper_LimitGPUsPerPod(t *testing.T) {
+	template, constraint, err := Gatekeeper(LimitGPUsPerPod, "rmc-global-config-limit-gpus-per-pod", multisuseiov1alpha1.PolicyConfig{LimitGPUsPerPod: 2})
+	require.NoError(t, err)
+
+	assert.Equal(t, ConstraintTemplateGVK, template.GroupVersionKind())
+	assert.Equal(t, "k8sgpulimit", template.GetName())
+	kind, _, _ := unstructured.NestedString(template.Object, "spec", "crd", "spec", "names", "kind")
+	assert.Equal(t, "K8sGPULimit", kind)
+	targets, _, _ := unstructured.NestedSlice(template.Object, "spec", "targets")
+	rego := targets[0].(map[string]interface{})["rego"]
+	assert.Contains(t, rego, "package k8sgpulimit")
+	// GPUs add up across containers, init containers run one at a time
+	assert.Contains(t, rego, "sum([container_gpus(c, resource) | c := input.review.object.spec.containers[_]])")
+	assert.Contains(t, rego, "max(array.concat([containers], init))")
+
+	gvk, err := GatekeeperConstraintGVK(LimitGPUsPerPod)
+	require.NoError(t, err)
+	assert.Equal(t, gvk, constraint.GroupVersionKind())
+	assert.Equal(t, "constraints.gatekeeper.sh", gvk.Group)
+	assert.Equal(t, "rmc-global-config-limit-gpus-per-pod", constraint.GetName())
+	limit, _, _ := unstructured.NestedInt64(constraint.Object, "spec", "parameters", "limit")
+	assert.Equal(t, int64(2), limit)
+	resources, _, _ := unstructured.NestedStringSlice(constraint.Object, "spec", "parameters", "resources")
+	assert.Contains(t, resources, "amd.com/gpu")
+
+	assert.NotPanics(t, func() { template.DeepCopy(); constraint.DeepCopy() })
+}
+
+func TestGatekeeper_RestrictGPUNamespaces(t *testing.T) {
+	_, constraint, err := Gatekeeper(RestrictGPUNamespaces, "restrict", multisuseiov1alpha1.PolicyConfig{RestrictGPUNamespaces: true})
+	require.NoError(t, err)
+
+	expressions, _, _ := unstructured.NestedSlice(constraint.Object, "spec", "match", "namespaceSelector", "matchExpressions")
+	require.Len(t, expressions, 1)
+	assert.Equal(t, GPUWorkloadsLabel, expressions[0].(map[string]interface{})["key"])
+	assert.Equal(t, "NotIn", expressions[0].(map[string]interface{})["operator"])
+	resources, _, _ := unstructured.NestedStringSlice(constraint.Object, "spec", "parameters", "resources")
+	assert.Equal(t, []string{"nvidia.com/gpu", "amd.com/gpu", "gpu.intel.com/i915", "gpu.intel.com/xe"}, resources)
+	// Shares of a GPU, such as gpu.intel.com/millicores, are matched by their vendor's prefix
+	prefixes, _, _ := unstructured.NestedStringSlice(constraint.Object, "spec", "parameters", "resourcePrefixes")
+	assert.Equal(t, GPUResourcePrefixes, prefixes)
+	assert.NotPanics(t, func() { constraint.DeepCopy() })
+}
+
+func TestGatekeeper_RestrictGPUNamespaces_AllowedNamespaces(t *testing.T) {
+	_, constraint, err := Gatekeeper(RestrictGPUNamespaces, "restrict", multisuseiov1alpha1.PolicyConfig{
+		RestrictGPUNamespaces: true,
+		AllowedNamespaces:     []string{"ml"},
+	})
+	require.NoError(t, err)
+	excluded, _, _ := unstructured.NestedStringSlice(constraint.Object, "spec", "match", "excludedNamespaces")
+	assert.Equal(t, []string{"ml"}, excluded)
+	_, found, _ := unstructured.NestedMap(constraint.Object, "spec", "match", "namespaceSelector")
+	assert.False(t, found)
+
+	_, constraint, err = Gatekeeper(RestrictGPUNamespaces, "restrict", multisuseiov1alpha1.PolicyConfig{
+		RestrictGPUNamespaces: true,
+		NamespaceSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{
+			Key:      "team",
+			Operator: metav1.LabelSelectorOpExists,
+		}}},
+	})
+	require.NoError(t, err)
+	expressions, _, _ := unstructured.NestedSlice(constraint.Object, "spec", "match", "namespaceSelector", "matchExpressions")
+	require.Len(t, expressions, 1)
+	assert.Equal(t, "DoesNotExist", expressions[0].(map[string]interface{})["operator"])
+
+	// Gatekeeper cannot exclude namespaces matching several requirements
+	_, _, err = Gatekeeper(RestrictGPUNamespaces, "restrict", multisuseiov1alpha1.PolicyConfig{
+		RestrictGPUNamespaces: true,
+		NamespaceSelector:     &metav1.LabelSelector{MatchLabels: map[string]string{"team": "ai", "tier": "gpu"}},
+	})
+	assert.Error(t, err)
+}
+
+func TestGatekeeper_EnforceRuntimeClass(t *testing.T) {
+	template, constraint, err := Gatekeeper(EnforceRuntimeClass, "runtime", multisuseiov1alpha1.PolicyConfig{
+		EnforceRuntimeClass: true,
+		RuntimeClasses:      map[string]string{"intel": "kata-intel"},
+	})
+	require.NoError(t, err)
+
+	// The template enforces the class of each resource instead of only a non-empty class
+	targets, _, _ := unstructured.NestedSlice(template.Object, "spec", "targets")
+	assert.Contains(t, targets[0].(map[string]interface{})["rego"], `object.get(input.review.object.spec, "runtimeClassName", "") != class`)
+
+	resources, _, _ := unstructured.NestedSlice(constraint.Object, "spec", "parameters", "resources")
+	classes := map[string]interface{}{}
+	for _, r := range resources {
+		resource := r.(map[string]interface{})
+		classes[resource["name"].(string)] = resource["runtimeClass"]
+	}
+	assert.Equal(t, map[string]interface{}{
+		"nvidia.com/gpu":     "nvidia",
+		"amd.com/gpu":        nil,
+		"gpu.intel.com/i915": "kata-intel",
+		"gpu.intel.com/xe":   "kata-intel",
+	}, classes)
+	assert.NotPanics(t, func() { constraint.DeepCopy() })
+}
+
+func TestRender(t *testing.T) {
+	config := multisuseiov1alpha1.PolicyConfig{LimitGPUsPerPod: 4}
+
+	objects, err := Render(Engine(config), LimitGPUsPerPod, "limit", config)
+	require.NoError(t, err)
+	require.Len(t, objects, 1)
+	assert.Equal(t, KyvernoClusterPolicyGVK, objects[0].GroupVersionKind())
+
+	objects, err = Render(multisuseiov1alpha1.PolicyEngineGatekeeper, LimitGPUsPerPod, "limit", config)
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+	assert.Equal(t, "limit", objects[1].GetName())
+
+	owned, err := Owned(multisuseiov1alpha1.PolicyEngineGatekeeper, LimitGPUsPerPod, "limit")
+	require.NoError(t, err)
+	assert.Equal(t, objects[1].GroupVersionKind(), owned.GroupVersionKind())
+
+	_, err = Render("opa", LimitGPUsPerPod, "limit", config)
+	assert.Error(t, err)
+}
+
+func TestGatekeeper_Audit(t *testing.T) {
+	_, constraint, err := Gatekeeper(LimitGPUsPerPod, "limit", multisuseiov1alpha1.PolicyConfig{LimitGPUsPerPod: 1})
+	require.NoError(t, err)
+	action, _, _ := unstructured.NestedString(constraint.Object, "spec", "enforcementAction")
+	assert.Equal(t, "deny", action)
+
+	_, constraint, err = Gatekeeper(LimitGPUsPerPod, "limit", multisuseiov1alpha1.PolicyConfig{LimitGPUsPerPod: 1, Audit: true})
+	require.NoError(t, err)
+	action, _, _ = unstructured.NestedString(constraint.Object, "spec", "enforcementAction")
+	assert.Equal(t, "warn", action)
+}
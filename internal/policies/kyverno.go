@@ -0,0 +1,207 @@
+package policies
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	multisuseiov1alpha1 "github.com/suse/rancher-multi-compute/api/multi.suse.io/v1alpha1"
+)
+
+// KyvernoClusterPolicyGVK is the kind of the Kyverno policies
+var KyvernoClusterPolicyGVK = schema.GroupVersionKind{
+	Group:   "kyverno.io",
+	Version: "v1",
+	Kind:    "ClusterPolicy",
+}
+
+// kyvernoDescriptions annotate the rendered ClusterPolicies, by policy
+var kyvernoDescriptions = map[string]struct{ title, description string }{
+	LimitGPUsPerPod: {
+		title:       "Limit GPU resources per pod",
+		description: "Limits the number of GPU resources that can be requested by a single pod. This helps prevent resource exhaustion and ensures fair allocation.",
+	},
+	RestrictGPUNamespaces: {
+		title:       "Restrict GPU workloads to namespaces",
+		description: "Rejects pods requesting GPU resources outside the namespaces allowed to run GPU workloads, so tenants cannot take accelerators from arbitrary namespaces.",
+	},
+	EnforceRuntimeClass: {
+		title:       "Require the vendor RuntimeClass for GPU workloads",
+		description: "Requires pods requesting GPU resources to run with the RuntimeClass of the GPU vendor.",
+	},
+}
+
+// Kyverno renders the Kyverno ClusterPolicy, named name, enforcing a policy of config
+func Kyverno(policy, name string, config multisuseiov1alpha1.PolicyConfig) (*unstructured.Unstructured, error) {
+	var (
+		rules []interface{}
+		err   error
+	)
+	switch policy {
+	case LimitGPUsPerPod:
+		rules = kyvernoLimitRules(config.LimitGPUsPerPod)
+	case RestrictGPUNamespaces:
+		rules, err = kyvernoNamespaceRules(config)
+		if err != nil {
+			return nil, err
+		}
+	case EnforceRuntimeClass:
+		rules = kyvernoRuntimeClassRules(RuntimeClasses(config))
+	default:
+		return nil, fmt.Errorf("unknown policy %q", policy)
+	}
+
+	spec := map[string]interface{}{
+		"validationFailureAction": "Enforce",
+		"background":              true,
+		"rules":                   rules,
+	}
+	if config.Audit {
+		// Report violations and warn at admission instead of rejecting pods
+		spec["validationFailureAction"] = "Audit"
+		spec["emitWarning"] = true
+	}
+
+	annotations := kyvernoDescriptions[policy]
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				"policies.kyverno.io/title":       annotations.title,
+				"policies.kyverno.io/category":    "Multi-Tenancy",
+				"policies.kyverno.io/severity":    "medium",
+				"policies.kyverno.io/subject":     "Pod",
+				"policies.kyverno.io/description": annotations.description,
+			},
+		},
+		"spec": spec,
+	}}
+	obj.SetGroupVersionKind(KyvernoClusterPolicyGVK)
+	obj.SetName(name)
+	return obj, nil
+}
+
+// kyvernoLimitRules reject pods whose containers limit more than limit of any GPU resource in total.
+// Kubernetes requires extended resources to be limited, and requests to match the limits.
+func kyvernoLimitRules(limit int32) []interface{} {
+	conditions := make([]interface{}, 0, len(GPUResources))
+	for _, r := range GPUResources {
+		conditions = append(conditions, map[string]interface{}{
+			"key":      fmt.Sprintf("{{ %s }}", gpuLimitSum(r.Name)),
+			"operator": "GreaterThan",
+			"value":    int64(limit),
+		})
+	}
+	return []interface{}{map[string]interface{}{
+		"name":  "limit-gpu-resources",
+		"match": matchPods(),
+		"validate": map[string]interface{}{
+			"message": fmt.Sprintf("Pod cannot request more than %d GPU resources", limit),
+			"deny": map[string]interface{}{
+				"conditions": map[string]interface{}{"any": conditions},
+			},
+		},
+	}}
+}
+
+// gpuLimitSum is the JMESPath expression adding up the limits of a GPU resource across the containers
+// of the pod under admission
+func gpuLimitSum(resource string) string {
+	return fmt.Sprintf(`sum(map(&to_number(@), request.object.spec.containers[].resources.limits."%s"))`, resource)
+}
+
+// kyvernoNamespaceRules reject pods requesting GPU resources outside the allowed namespaces
+func kyvernoNamespaceRules(config multisuseiov1alpha1.PolicyConfig) ([]interface{}, error) {
+	var allowed []interface{}
+	if len(config.AllowedNamespaces) > 0 {
+		namespaces := make([]interface{}, 0, len(config.AllowedNamespaces))
+		for _, ns := range config.AllowedNamespaces {
+			namespaces = append(namespaces, ns)
+		}
+		allowed = append(allowed, map[string]interface{}{
+			"resources": map[string]interface{}{"namespaces": namespaces},
+		})
+	}
+	if selector := NamespaceSelector(config); selector != nil {
+		converted, err := runtime.DefaultUnstructuredConverter.ToUnstructured(selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid namespace selector: %w", err)
+		}
+		allowed = append(allowed, map[string]interface{}{
+			"resources": map[string]interface{}{"namespaceSelector": converted},
+		})
+	}
+
+	// Resource names requested or limited by any container, filtered down to restricted resources
+	filters := make([]string, 0, len(GPUResources)+len(GPUResourcePrefixes))
+	for _, r := range GPUResources {
+		filters = append(filters, fmt.Sprintf("@ == '%s'", r.Name))
+	}
+	for _, prefix := range GPUResourcePrefixes {
+		filters = append(filters, fmt.Sprintf("starts_with(@, '%s')", prefix))
+	}
+	requested := "{{ request.object.spec.containers[].keys(merge(resources.requests || `{}`, resources.limits || `{}`))[] | [?" +
+		strings.Join(filters, " || ") + "] | length(@) }}"
+
+	rule := map[string]interface{}{
+		"name":  "restrict-gpu-namespaces",
+		"match": matchPods(),
+		"validate": map[string]interface{}{
+			"message": "GPU resources can only be requested in the namespaces allowed to run GPU workloads",
+			"deny": map[string]interface{}{
+				"conditions": map[string]interface{}{
+					"any": []interface{}{map[string]interface{}{
+						"key":      requested,
+						"operator": "GreaterThan",
+						"value":    int64(0),
+					}},
+				},
+			},
+		},
+	}
+	if len(allowed) > 0 {
+		rule["exclude"] = map[string]interface{}{"any": allowed}
+	}
+	return []interface{}{rule}, nil
+}
+
+// kyvernoRuntimeClassRules require pods limiting a GPU resource to set the RuntimeClass of its vendor,
+// or any RuntimeClass for vendors without one
+func kyvernoRuntimeClassRules(classes map[string]string) []interface{} {
+	rules := make([]interface{}, 0, len(GPUResources))
+	for _, r := range GPUResources {
+		class, message := "?*", fmt.Sprintf("Pods requesting %s must set a RuntimeClass", r.Name)
+		if c, ok := classes[r.Vendor]; ok {
+			class, message = c, fmt.Sprintf("Pods requesting %s must use the %s RuntimeClass", r.Name, c)
+		}
+		rules = append(rules, map[string]interface{}{
+			"name":  "runtime-class-" + strings.NewReplacer(".", "-", "/", "-").Replace(r.Name),
+			"match": matchPods(),
+			"validate": map[string]interface{}{
+				"message": message,
+				"pattern": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"runtimeClassName": class,
+						"(containers)": []interface{}{map[string]interface{}{
+							"(resources)": map[string]interface{}{
+								"(limits)": map[string]interface{}{"(" + r.Name + ")": "?*"},
+							},
+						}},
+					},
+				},
+			},
+		})
+	}
+	return rules
+}
+
+// matchPods matches every Pod
+func matchPods() map[string]interface{} {
+	return map[string]interface{}{
+		"any": []interface{}{map[string]interface{}{
+			"resources": map[string]interface{}{"kinds": []interface{}{"Pod"}},
+		}},
+	}
+}
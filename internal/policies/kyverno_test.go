@@ -0,0 +1,224 @@
+package policies
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jmespath/go-jmespath"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	multisuseiov1alpha1 "github.com/suse/rancher-multi-compute/api/multi.suse.io/v1alpha1"
+)
+
+func rules(t *testing.T, obj *unstructured.Unstructured) []interface{} {
+	t.Helper()
+	rules, found, err := unstructured.NestedSlice(obj.Object, "spec", "rules")
+	require.NoError(t, err)
+	require.True(t, found)
+	return rules
+}
+
+func TestEnabled(t *testing.T) {
+	assert.Empty(t, Enabled(multisuseiov1alpha1.PolicyConfig{RequireCosign: true}))
+	assert.Equal(t, []string{LimitGPUsPerPod, EnforceRuntimeClass}, Enabled(multisuseiov1alpha1.PolicyConfig{
+		LimitGPUsPerPod:     2,
+		EnforceRuntimeClass: true,
+	}))
+}
+
+func TestIsGPUResource(t *testing.T) {
+	assert.True(t, IsGPUResource("nvidia.com/gpu"))
+	assert.True(t, IsGPUResource("amd.com/gpu"))
+	assert.True(t, IsGPUResource("gpu.intel.com/xe"))
+	assert.True(t, IsGPUResource("gpu.intel.com/i915"))
+	// Shared-GPU quantities are not devices
+	assert.False(t, IsGPUResource("gpu.intel.com/millicores"))
+	assert.False(t, IsGPUResource("gpu.intel.com/memory.max"))
+	assert.False(t, IsGPUResource("cpu"))
+	assert.False(t, IsGPUResource("nvidia.com/mig-1g.5gb"))
+}
+
+func TestIsRestrictedResource(t *testing.T) {
+	assert.True(t, IsRestrictedResource("nvidia.com/gpu"))
+	assert.True(t, IsRestrictedResource("gpu.intel.com/xe"))
+	// Shares of a GPU are restricted to the namespaces allowed to run GPU workloads too
+	assert.True(t, IsRestrictedResource("gpu.intel.com/millicores"))
+	assert.True(t, IsRestrictedResource("gpu.intel.com/memory.max"))
+	assert.False(t, IsRestrictedResource("cpu"))
+}
+
+func TestNamespaceSelector(t *testing.T) {
+	assert.Equal(t, &metav1.LabelSelector{MatchLabels: map[string]string{GPUWorkloadsLabel: "true"}},
+		NamespaceSelector(multisuseiov1alpha1.PolicyConfig{}))
+	assert.Nil(t, NamespaceSelector(multisuseiov1alpha1.PolicyConfig{AllowedNamespaces: []string{"ml"}}))
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"team": "ai"}}
+	assert.Equal(t, selector, NamespaceSelector(multisuseiov1alpha1.PolicyConfig{
+		AllowedNamespaces: []string{"ml"},
+		NamespaceSelector: selector,
+	}))
+}
+
+func TestKyverno_LimitGPUsPerPod(t *testing.T) {
+	obj, err := Kyverno(LimitGPUsPerPod, "rmc-global-config-limit-gpus-per-pod", multisuseiov1alpha1.PolicyConfig{LimitGPUsPerPod: 2})
+	require.NoError(t, err)
+
+	assert.Equal(t, KyvernoClusterPolicyGVK, obj.GroupVersionKind())
+	assert.Equal(t, "rmc-global-config-limit-gpus-per-pod", obj.GetName())
+	action, _, _ := unstructured.NestedString(obj.Object, "spec", "validationFailureAction")
+	assert.Equal(t, "Enforce", action)
+
+	rule := rules(t, obj)[0].(map[string]interface{})
+	message, _, _ := unstructured.NestedString(rule, "validate", "message")
+	assert.Equal(t, "Pod cannot request more than 2 GPU resources", message)
+	conditions, _, _ := unstructured.NestedSlice(rule, "validate", "deny", "conditions", "any")
+	require.Len(t, conditions, len(GPUResources))
+	for _, c := range conditions {
+		condition := c.(map[string]interface{})
+		assert.Equal(t, "GreaterThan", condition["operator"])
+		assert.Equal(t, int64(2), condition["value"])
+	}
+
+	// Rendered objects must be deep-copyable to be applied
+	assert.NotPanics(t, func() { obj.DeepCopy() })
+}
+
+// denied evaluates the deny conditions of a Kyverno rule against the admission of pod, as Kyverno does
+func denied(t *testing.T, rule map[string]interface{}, pod map[string]interface{}) bool {
+	conditions, _, _ := unstructured.NestedSlice(rule, "validate", "deny", "conditions", "any")
+	request := map[string]interface{}{"request": map[string]interface{}{"object": pod}}
+	for _, c := range conditions {
+		condition := c.(map[string]interface{})
+		require.Equal(t, "GreaterThan", condition["operator"])
+		expression := strings.TrimSuffix(strings.TrimPrefix(condition["key"].(string), "{{ "), " }}")
+		value, err := jmespath.Search(expression, request)
+		require.NoError(t, err, expression)
+		if value.(float64) > float64(condition["value"].(int64)) {
+			return true
+		}
+	}
+	return false
+}
+
+// podLimiting returns a pod with a container limiting each of the given GPU quantities
+func podLimiting(limits ...map[string]interface{}) map[string]interface{} {
+	containers := make([]interface{}, 0, len(limits))
+	for i, l := range limits {
+		containers = append(containers, map[string]interface{}{
+			"name":      fmt.Sprintf("c%d", i),
+			"resources": map[string]interface{}{"limits": l},
+		})
+	}
+	return map[string]interface{}{"spec": map[string]interface{}{"containers": containers}}
+}
+
+func TestKyverno_LimitGPUsPerPod_MultipleContainers(t *testing.T) {
+	obj, err := Kyverno(LimitGPUsPerPod, "limit", multisuseiov1alpha1.PolicyConfig{LimitGPUsPerPod: 2})
+	require.NoError(t, err)
+	rule := rules(t, obj)[0].(map[string]interface{})
+
+	assert.False(t, denied(t, rule, podLimiting(map[string]interface{}{"nvidia.com/gpu": "2", "cpu": "500m"})))
+	assert.False(t, denied(t, rule, podLimiting(map[string]interface{}{"cpu": "1"}, map[string]interface{}{})))
+	// Containers within the limit on their own add up over it
+	assert.True(t, denied(t, rule, podLimiting(
+		map[string]interface{}{"nvidia.com/gpu": "1"},
+		map[string]interface{}{"nvidia.com/gpu": "1"},
+		map[string]interface{}{"nvidia.com/gpu": "1"},
+	)))
+	assert.True(t, denied(t, rule, podLimiting(map[string]interface{}{"gpu.intel.com/xe": "3"})))
+	// The limit applies per vendor
+	assert.False(t, denied(t, rule, podLimiting(
+		map[string]interface{}{"nvidia.com/gpu": "2"},
+		map[string]interface{}{"amd.com/gpu": "2"},
+	)))
+}
+
+func TestKyverno_RestrictGPUNamespaces(t *testing.T) {
+	obj, err := Kyverno(RestrictGPUNamespaces, "restrict", multisuseiov1alpha1.PolicyConfig{RestrictGPUNamespaces: true})
+	require.NoError(t, err)
+
+	rule := rules(t, obj)[0].(map[string]interface{})
+	excluded, _, _ := unstructured.NestedSlice(rule, "exclude", "any")
+	require.Len(t, excluded, 1)
+	labels, _, _ := unstructured.NestedStringMap(excluded[0].(map[string]interface{}), "resources", "namespaceSelector", "matchLabels")
+	assert.Equal(t, map[string]string{GPUWorkloadsLabel: "true"}, labels)
+
+	conditions, _, _ := unstructured.NestedSlice(rule, "validate", "deny", "conditions", "any")
+	condition := conditions[0].(map[string]interface{})
+	assert.Contains(t, condition["key"], "@ == 'amd.com/gpu'")
+	assert.Contains(t, condition["key"], "@ == 'gpu.intel.com/xe'")
+	assert.Contains(t, condition["key"], "starts_with(@, 'gpu.intel.com/')")
+	assert.Equal(t, "GreaterThan", condition["operator"])
+	assert.NotPanics(t, func() { obj.DeepCopy() })
+
+	// Outside the allowed namespaces, shares of a GPU are rejected like whole devices
+	assert.True(t, denied(t, rule, podLimiting(map[string]interface{}{"gpu.intel.com/millicores": "500"})))
+	assert.True(t, denied(t, rule, podLimiting(map[string]interface{}{"cpu": "1"}, map[string]interface{}{"nvidia.com/gpu": "1"})))
+	assert.False(t, denied(t, rule, podLimiting(map[string]interface{}{"cpu": "1"})))
+}
+
+func TestKyverno_RestrictGPUNamespaces_AllowedNamespaces(t *testing.T) {
+	obj, err := Kyverno(RestrictGPUNamespaces, "restrict", multisuseiov1alpha1.PolicyConfig{
+		RestrictGPUNamespaces: true,
+		AllowedNamespaces:     []string{"ml", "research"},
+	})
+	require.NoError(t, err)
+
+	rule := rules(t, obj)[0].(map[string]interface{})
+	excluded, _, _ := unstructured.NestedSlice(rule, "exclude", "any")
+	require.Len(t, excluded, 1)
+	namespaces, _, _ := unstructured.NestedStringSlice(excluded[0].(map[string]interface{}), "resources", "namespaces")
+	assert.Equal(t, []string{"ml", "research"}, namespaces)
+
+	obj, err = Kyverno(RestrictGPUNamespaces, "restrict", multisuseiov1alpha1.PolicyConfig{
+		RestrictGPUNamespaces: true,
+		AllowedNamespaces:     []string{"ml"},
+		NamespaceSelector:     &metav1.LabelSelector{MatchLabels: map[string]string{"team": "ai"}},
+	})
+	require.NoError(t, err)
+	rule = rules(t, obj)[0].(map[string]interface{})
+	excluded, _, _ = unstructured.NestedSlice(rule, "exclude", "any")
+	require.Len(t, excluded, 2)
+	labels, _, _ := unstructured.NestedStringMap(excluded[1].(map[string]interface{}), "resources", "namespaceSelector", "matchLabels")
+	assert.Equal(t, map[string]string{"team": "ai"}, labels)
+}
+
+func TestKyverno_EnforceRuntimeClass(t *testing.T) {
+	obj, err := Kyverno(EnforceRuntimeClass, "runtime", multisuseiov1alpha1.PolicyConfig{
+		EnforceRuntimeClass: true,
+		RuntimeClasses:      map[string]string{"amd": "rocm"},
+	})
+	require.NoError(t, err)
+
+	classes := map[string]string{}
+	for _, r := range rules(t, obj) {
+		rule := r.(map[string]interface{})
+		class, _, _ := unstructured.NestedString(rule, "validate", "pattern", "spec", "runtimeClassName")
+		classes[rule["name"].(string)] = class
+	}
+	assert.Equal(t, map[string]string{
+		"runtime-class-nvidia-com-gpu":     "nvidia",
+		"runtime-class-amd-com-gpu":        "rocm",
+		"runtime-class-gpu-intel-com-i915": "?*",
+		"runtime-class-gpu-intel-com-xe":   "?*",
+	}, classes)
+	assert.NotPanics(t, func() { obj.DeepCopy() })
+}
+
+func TestKyverno_UnknownPolicy(t *testing.T) {
+	_, err := Kyverno("require-cosign", "cosign", multisuseiov1alpha1.PolicyConfig{})
+	assert.Error(t, err)
+}
+
+func TestKyverno_Audit(t *testing.T) {
+	obj, err := Kyverno(LimitGPUsPerPod, "limit", multisuseiov1alpha1.PolicyConfig{LimitGPUsPerPod: 1, Audit: true})
+	require.NoError(t, err)
+
+	action, _, _ := unstructured.NestedString(obj.Object, "spec", "validationFailureAction")
+	assert.Equal(t, "Audit", action)
+	warn, _, _ := unstructured.NestedBool(obj.Object, "spec", "emitWarning")
+	assert.True(t, warn)
+}
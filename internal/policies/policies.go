@@ -0,0 +1,171 @@
+package policies
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	multisuseiov1alpha1 "github.com/suse/rancher-multi-compute/api/multi.suse.io/v1alpha1"
+)
+
+// Policies enforcing a PolicyConfig, one per setting
+const (
+	LimitGPUsPerPod       = "limit-gpus-per-pod"
+	RestrictGPUNamespaces = "restrict-gpu-namespaces"
+	EnforceRuntimeClass   = "enforce-runtime-class"
+)
+
+// Names lists every policy
+var Names = []string{LimitGPUsPerPod, RestrictGPUNamespaces, EnforceRuntimeClass}
+
+// Engines lists the policy engines enforcing the policies with rendered objects. The webhook engine
+// evaluates pods with Validate instead.
+var Engines = []string{multisuseiov1alpha1.PolicyEngineKyverno, multisuseiov1alpha1.PolicyEngineGatekeeper}
+
+// GPUWorkloadsLabel opts a namespace in to GPU workloads when GPU namespaces are restricted
+const GPUWorkloadsLabel = "multi.suse.io/gpu-workloads"
+
+// GPUResource is an extended resource advertised by a vendor's device plugin
+type GPUResource struct {
+	Vendor string
+	Name   string
+}
+
+// GPUResources are the GPU resources policies apply to
+var GPUResources = []GPUResource{
+	{Vendor: "nvidia", Name: "nvidia.com/gpu"},
+	{Vendor: "amd", Name: "amd.com/gpu"},
+	{Vendor: "intel", Name: "gpu.intel.com/i915"},
+	{Vendor: "intel", Name: "gpu.intel.com/xe"},
+}
+
+// GPUResourcePrefixes match the resources of vendors advertising GPUs and shares of GPUs under a common
+// domain, such as gpu.intel.com/i915 and gpu.intel.com/millicores
+var GPUResourcePrefixes = []string{"gpu.intel.com/"}
+
+// IsGPUResource reports whether a resource name is a GPU resource
+func IsGPUResource(name string) bool {
+	_, ok := GPUVendor(name)
+	return ok
+}
+
+// GPUVendor returns the vendor of a GPU resource, and whether name is a GPU resource. Only whole
+// devices count: shared-GPU resources such as gpu.intel.com/millicores and gpu.intel.com/memory.max
+// are quantities of a device, not devices.
+func GPUVendor(name string) (string, bool) {
+	for _, r := range GPUResources {
+		if r.Name == name {
+			return r.Vendor, true
+		}
+	}
+	return "", false
+}
+
+// IsRestrictedResource reports whether requesting a resource is restricted to the namespaces allowed to
+// run GPU workloads: GPU resources, and any resource of a GPUResourcePrefixes domain, shares of a GPU included
+func IsRestrictedResource(name string) bool {
+	if IsGPUResource(name) {
+		return true
+	}
+	for _, prefix := range GPUResourcePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultRuntimeClasses are the RuntimeClasses required per vendor unless configured
+var DefaultRuntimeClasses = map[string]string{"nvidia": "nvidia"}
+
+// Enabled returns the policies a PolicyConfig enables, in the order of Names
+func Enabled(config multisuseiov1alpha1.PolicyConfig) []string {
+	var enabled []string
+	if config.LimitGPUsPerPod > 0 {
+		enabled = append(enabled, LimitGPUsPerPod)
+	}
+	if config.RestrictGPUNamespaces {
+		enabled = append(enabled, RestrictGPUNamespaces)
+	}
+	if config.EnforceRuntimeClass {
+		enabled = append(enabled, EnforceRuntimeClass)
+	}
+	return enabled
+}
+
+// RuntimeClasses returns the RuntimeClass required per vendor, the configured ones over the defaults
+func RuntimeClasses(config multisuseiov1alpha1.PolicyConfig) map[string]string {
+	classes := make(map[string]string, len(DefaultRuntimeClasses)+len(config.RuntimeClasses))
+	for vendor, class := range DefaultRuntimeClasses {
+		classes[vendor] = class
+	}
+	for vendor, class := range config.RuntimeClasses {
+		classes[vendor] = class
+	}
+	return classes
+}
+
+// NamespaceSelector returns the selector of the namespaces allowed to run GPU workloads besides
+// config.AllowedNamespaces. Without any, namespaces labeled GPUWorkloadsLabel=true are allowed; nil
+// is returned when only the listed namespaces are.
+func NamespaceSelector(config multisuseiov1alpha1.PolicyConfig) *metav1.LabelSelector {
+	switch {
+	case config.NamespaceSelector != nil:
+		return config.NamespaceSelector
+	case len(config.AllowedNamespaces) > 0:
+		return nil
+	default:
+		return &metav1.LabelSelector{MatchLabels: map[string]string{GPUWorkloadsLabel: "true"}}
+	}
+}
+
+// Engine returns the policy engine of a PolicyConfig, Kyverno unless set
+func Engine(config multisuseiov1alpha1.PolicyConfig) string {
+	if config.Engine == "" {
+		return multisuseiov1alpha1.PolicyEngineKyverno
+	}
+	return config.Engine
+}
+
+// Render renders the objects enforcing a policy of config with an engine. The object specific to the
+// policy of config, a Kyverno ClusterPolicy or a Gatekeeper constraint, is named name and comes last.
+func Render(engine, policy, name string, config multisuseiov1alpha1.PolicyConfig) ([]*unstructured.Unstructured, error) {
+	switch engine {
+	case multisuseiov1alpha1.PolicyEngineKyverno:
+		clusterPolicy, err := Kyverno(policy, name, config)
+		if err != nil {
+			return nil, err
+		}
+		return []*unstructured.Unstructured{clusterPolicy}, nil
+	case multisuseiov1alpha1.PolicyEngineGatekeeper:
+		template, constraint, err := Gatekeeper(policy, name, config)
+		if err != nil {
+			return nil, err
+		}
+		return []*unstructured.Unstructured{template, constraint}, nil
+	default:
+		return nil, fmt.Errorf("unsupported policy engine %q", engine)
+	}
+}
+
+// Owned returns a reference to the object, named name, an engine enforces a policy with. Shared
+// objects such as Gatekeeper constraint templates are not included.
+func Owned(engine, policy, name string) (*unstructured.Unstructured, error) {
+	obj := &unstructured.Unstructured{}
+	switch engine {
+	case multisuseiov1alpha1.PolicyEngineKyverno:
+		obj.SetGroupVersionKind(KyvernoClusterPolicyGVK)
+	case multisuseiov1alpha1.PolicyEngineGatekeeper:
+		gvk, err := GatekeeperConstraintGVK(policy)
+		if err != nil {
+			return nil, err
+		}
+		obj.SetGroupVersionKind(gvk)
+	default:
+		return nil, fmt.Errorf("unsupported policy engine %q", engine)
+	}
+	obj.SetName(name)
+	return obj, nil
+}
@@ -0,0 +1,158 @@
+package policies
+
+import (
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	multisuseiov1alpha1 "github.com/suse/rancher-multi-compute/api/multi.suse.io/v1alpha1"
+)
+
+// Violation is a policy a pod does not comply with
+type Violation struct {
+	Policy  string
+	Message string
+}
+
+func (v Violation) String() string {
+	return v.Policy + ": " + v.Message
+}
+
+// Validate returns the violations by a pod, created in namespace, of the policies config enables, as
+// enforced by the webhook engine. Unlike the Kyverno and Gatekeeper policies, the GPUs of every
+// vendor requested by the pod add up towards LimitGPUsPerPod.
+func Validate(pod *corev1.Pod, namespace *corev1.Namespace, config multisuseiov1alpha1.PolicyConfig) ([]Violation, error) {
+	requested := PodGPUs(pod)
+	restricted := requestsRestricted(pod)
+	if len(requested) == 0 && !restricted {
+		return nil, nil
+	}
+	names := make([]string, 0, len(requested))
+	var total int64
+	for name, quantity := range requested {
+		names = append(names, name)
+		total += quantity
+	}
+	sort.Strings(names)
+
+	var violations []Violation
+	for _, policy := range Enabled(config) {
+		switch policy {
+		case LimitGPUsPerPod:
+			if total > int64(config.LimitGPUsPerPod) {
+				violations = append(violations, Violation{
+					Policy:  policy,
+					Message: fmt.Sprintf("pod requests %d GPUs, more than the limit of %d", total, config.LimitGPUsPerPod),
+				})
+			}
+		case RestrictGPUNamespaces:
+			allowed, err := namespaceAllowed(namespace, config)
+			if err != nil {
+				return nil, err
+			}
+			if restricted && !allowed {
+				violations = append(violations, Violation{
+					Policy:  policy,
+					Message: fmt.Sprintf("GPU resources cannot be requested in namespace %s, which does not allow GPU workloads", namespace.Name),
+				})
+			}
+		case EnforceRuntimeClass:
+			classes := RuntimeClasses(config)
+			runtimeClass := ""
+			if pod.Spec.RuntimeClassName != nil {
+				runtimeClass = *pod.Spec.RuntimeClassName
+			}
+			for _, name := range names {
+				vendor, _ := GPUVendor(name)
+				class, ok := classes[vendor]
+				switch {
+				case ok && runtimeClass != class:
+					violations = append(violations, Violation{
+						Policy:  policy,
+						Message: fmt.Sprintf("pods requesting %s must use the %s RuntimeClass", name, class),
+					})
+				case !ok && runtimeClass == "":
+					violations = append(violations, Violation{
+						Policy:  policy,
+						Message: fmt.Sprintf("pods requesting %s must set a RuntimeClass", name),
+					})
+				}
+			}
+		}
+	}
+	return violations, nil
+}
+
+// PodGPUs returns the quantity of each GPU resource a pod requests, the most of its init containers
+// or the sum of its containers
+func PodGPUs(pod *corev1.Pod) map[string]int64 {
+	requested := map[string]int64{}
+	for _, c := range pod.Spec.Containers {
+		for name, quantity := range containerGPUs(c) {
+			requested[name] += quantity
+		}
+	}
+	for _, c := range pod.Spec.InitContainers {
+		for name, quantity := range containerGPUs(c) {
+			if quantity > requested[name] {
+				requested[name] = quantity
+			}
+		}
+	}
+	for name, quantity := range requested {
+		if quantity == 0 {
+			delete(requested, name)
+		}
+	}
+	return requested
+}
+
+// containerGPUs returns the GPU resources of a container. Extended resources cannot be overcommitted,
+// so limits take precedence over requests, which default to them.
+func containerGPUs(c corev1.Container) map[string]int64 {
+	gpus := map[string]int64{}
+	for _, list := range []corev1.ResourceList{c.Resources.Requests, c.Resources.Limits} {
+		for name, quantity := range list {
+			if IsGPUResource(string(name)) {
+				gpus[string(name)] = quantity.Value()
+			}
+		}
+	}
+	return gpus
+}
+
+// requestsRestricted reports whether any container of a pod requests or limits a restricted resource
+func requestsRestricted(pod *corev1.Pod) bool {
+	containers := append(append([]corev1.Container(nil), pod.Spec.InitContainers...), pod.Spec.Containers...)
+	for _, c := range containers {
+		for _, list := range []corev1.ResourceList{c.Resources.Requests, c.Resources.Limits} {
+			for name := range list {
+				if IsRestrictedResource(string(name)) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// namespaceAllowed reports whether a namespace is allowed to run GPU workloads by config
+func namespaceAllowed(namespace *corev1.Namespace, config multisuseiov1alpha1.PolicyConfig) (bool, error) {
+	for _, ns := range config.AllowedNamespaces {
+		if ns == namespace.Name {
+			return true, nil
+		}
+	}
+	selector := NamespaceSelector(config)
+	if selector == nil {
+		return false, nil
+	}
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return false, fmt.Errorf("invalid namespace selector: %w", err)
+	}
+	return s.Matches(labels.Set(namespace.Labels)), nil
+}
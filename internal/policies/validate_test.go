@@ -0,0 +1,142 @@
+package policies
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	multisuseiov1alpha1 "github.com/suse/rancher-multi-compute/api/multi.suse.io/v1alpha1"
+)
+
+func gpuContainer(name string, limits map[string]string) corev1.Container {
+	c := corev1.Container{Name: name, Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{}}}
+	for resourceName, quantity := range limits {
+		c.Resources.Limits[corev1.ResourceName(resourceName)] = resource.MustParse(quantity)
+	}
+	return c
+}
+
+func namespace(name string, labels map[string]string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func TestPodGPUs(t *testing.T) {
+	pod := &corev1.Pod{Spec: corev1.PodSpec{
+		InitContainers: []corev1.Container{gpuContainer("init", map[string]string{"nvidia.com/gpu": "3"})},
+		Containers: []corev1.Container{
+			gpuContainer("a", map[string]string{"nvidia.com/gpu": "1", "cpu": "2"}),
+			gpuContainer("b", map[string]string{"nvidia.com/gpu": "1", "gpu.intel.com/xe": "0"}),
+			{
+				Name: "c",
+				Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+					"amd.com/gpu": resource.MustParse("2"),
+				}},
+			},
+		},
+	}}
+	assert.Equal(t, map[string]int64{"nvidia.com/gpu": 3, "amd.com/gpu": 2}, PodGPUs(pod))
+}
+
+func TestValidate_LimitGPUsPerPod(t *testing.T) {
+	config := multisuseiov1alpha1.PolicyConfig{LimitGPUsPerPod: 2}
+	pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{
+		gpuContainer("a", map[string]string{"nvidia.com/gpu": "1"}),
+		gpuContainer("b", map[string]string{"amd.com/gpu": "1"}),
+	}}}
+
+	violations, err := Validate(pod, namespace("default", nil), config)
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+
+	// GPUs of every vendor count towards the limit
+	pod.Spec.Containers = append(pod.Spec.Containers, gpuContainer("c", map[string]string{"gpu.intel.com/xe": "1"}))
+	violations, err = Validate(pod, namespace("default", nil), config)
+	require.NoError(t, err)
+	assert.Equal(t, []Violation{{
+		Policy:  LimitGPUsPerPod,
+		Message: "pod requests 3 GPUs, more than the limit of 2",
+	}}, violations)
+}
+
+func TestValidate_SharedGPUResources(t *testing.T) {
+	config := multisuseiov1alpha1.PolicyConfig{LimitGPUsPerPod: 1, RestrictGPUNamespaces: true, EnforceRuntimeClass: true}
+	pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{
+		Name: "a",
+		Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+			"gpu.intel.com/millicores": resource.MustParse("500"),
+			"gpu.intel.com/memory.max": resource.MustParse("4Gi"),
+		}},
+	}}}}
+
+	// A share of a GPU is not a GPU of its own, but is restricted like one
+	assert.Empty(t, PodGPUs(pod))
+	violations, err := Validate(pod, namespace("default", nil), config)
+	require.NoError(t, err)
+	assert.Equal(t, []Violation{
+		{Policy: RestrictGPUNamespaces, Message: "GPU resources cannot be requested in namespace default, which does not allow GPU workloads"},
+	}, violations)
+	violations, err = Validate(pod, namespace("ml", map[string]string{GPUWorkloadsLabel: "true"}), config)
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+
+	pod.Spec.Containers[0].Resources.Requests["gpu.intel.com/i915"] = resource.MustParse("1")
+	assert.Equal(t, map[string]int64{"gpu.intel.com/i915": 1}, PodGPUs(pod))
+	violations, err = Validate(pod, namespace("default", nil), config)
+	require.NoError(t, err)
+	assert.Equal(t, []Violation{
+		{Policy: RestrictGPUNamespaces, Message: "GPU resources cannot be requested in namespace default, which does not allow GPU workloads"},
+		{Policy: EnforceRuntimeClass, Message: "pods requesting gpu.intel.com/i915 must set a RuntimeClass"},
+	}, violations)
+}
+
+func TestValidate_RestrictGPUNamespaces(t *testing.T) {
+	pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{
+		gpuContainer("a", map[string]string{"gpu.intel.com/i915": "1"}),
+	}}}
+
+	config := multisuseiov1alpha1.PolicyConfig{RestrictGPUNamespaces: true}
+	violations, err := Validate(pod, namespace("default", nil), config)
+	require.NoError(t, err)
+	require.Len(t, violations, 1)
+	assert.Equal(t, RestrictGPUNamespaces, violations[0].Policy)
+	violations, err = Validate(pod, namespace("ml", map[string]string{GPUWorkloadsLabel: "true"}), config)
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+
+	config.AllowedNamespaces = []string{"ml"}
+	violations, err = Validate(pod, namespace("ml", nil), config)
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+	violations, err = Validate(pod, namespace("research", map[string]string{GPUWorkloadsLabel: "true"}), config)
+	require.NoError(t, err)
+	assert.Len(t, violations, 1)
+
+	// Pods without GPUs are allowed anywhere
+	violations, err = Validate(&corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "web"}}}}, namespace("default", nil), config)
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+}
+
+func TestValidate_EnforceRuntimeClass(t *testing.T) {
+	config := multisuseiov1alpha1.PolicyConfig{EnforceRuntimeClass: true}
+	pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{
+		gpuContainer("a", map[string]string{"nvidia.com/gpu": "1", "amd.com/gpu": "1"}),
+	}}}
+
+	violations, err := Validate(pod, namespace("default", nil), config)
+	require.NoError(t, err)
+	assert.Equal(t, []Violation{
+		{Policy: EnforceRuntimeClass, Message: "pods requesting amd.com/gpu must set a RuntimeClass"},
+		{Policy: EnforceRuntimeClass, Message: "pods requesting nvidia.com/gpu must use the nvidia RuntimeClass"},
+	}, violations)
+
+	class := "nvidia"
+	pod.Spec.RuntimeClassName = &class
+	violations, err = Validate(pod, namespace("default", nil), config)
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+}
@@ -0,0 +1,186 @@
+package rollout
+
+import (
+	"fmt"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	multisuseiov1alpha1 "github.com/suse/rancher-multi-compute/api/multi.suse.io/v1alpha1"
+	"github.com/suse/rancher-multi-compute/internal/drift"
+	"github.com/suse/rancher-multi-compute/internal/fleetutil"
+)
+
+// remainingWaveName names the implicit last wave holding clusters matched by no explicit wave
+const remainingWaveName = "remaining"
+
+// Cluster is a Fleet cluster considered for a rollout
+type Cluster struct {
+	Name   string
+	Labels map[string]string
+}
+
+// Wave is a group of clusters updated together
+type Wave struct {
+	Name string
+	// Selector targets the wave by labels; nil when the wave is a fixed batch of clusters
+	Selector *metav1.LabelSelector
+	Clusters []string
+}
+
+// Plan splits the clusters matched by the channel selector into ordered waves.
+// Without a strategy all clusters form a single wave.
+func Plan(selector metav1.LabelSelector, strategy *multisuseiov1alpha1.RolloutStrategy, clusters []Cluster) ([]Wave, error) {
+	matched, err := matching(selector, clusters)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case strategy != nil && len(strategy.Waves) > 0:
+		return planWaves(selector, strategy.Waves, matched)
+	case strategy != nil && strategy.MaxUnavailable != nil:
+		return planBatches(*strategy.MaxUnavailable, matched)
+	default:
+		return []Wave{{Name: "all", Selector: &selector, Clusters: names(matched)}}, nil
+	}
+}
+
+// planWaves assigns each cluster to the first wave selecting it, like Fleet's first-match target semantics
+func planWaves(selector metav1.LabelSelector, specs []multisuseiov1alpha1.RolloutWave, clusters []Cluster) ([]Wave, error) {
+	assigned := map[string]bool{}
+	waves := make([]Wave, 0, len(specs)+1)
+	for _, spec := range specs {
+		merged := fleetutil.MergeSelectors(selector, spec.ClusterSelector)
+		matched, err := matching(merged, clusters)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector for wave %s: %w", spec.Name, err)
+		}
+		wave := Wave{Name: spec.Name, Selector: &merged}
+		for _, c := range matched {
+			if !assigned[c.Name] {
+				assigned[c.Name] = true
+				wave.Clusters = append(wave.Clusters, c.Name)
+			}
+		}
+		waves = append(waves, wave)
+	}
+
+	remaining := Wave{Name: remainingWaveName, Selector: &selector}
+	for _, c := range clusters {
+		if !assigned[c.Name] {
+			remaining.Clusters = append(remaining.Clusters, c.Name)
+		}
+	}
+	return append(waves, remaining), nil
+}
+
+// planBatches splits clusters, sorted by name, into batches of at most maxUnavailable clusters
+func planBatches(maxUnavailable intstr.IntOrString, clusters []Cluster) ([]Wave, error) {
+	size, err := intstr.GetScaledValueFromIntOrPercent(&maxUnavailable, len(clusters), true)
+	if err != nil {
+		return nil, fmt.Errorf("invalid maxUnavailable: %w", err)
+	}
+	if size < 1 {
+		size = 1
+	}
+
+	all := names(clusters)
+	var waves []Wave
+	for start := 0; start < len(all); start += size {
+		end := start + size
+		if end > len(all) {
+			end = len(all)
+		}
+		waves = append(waves, Wave{
+			Name:     fmt.Sprintf("batch-%d", len(waves)+1),
+			Clusters: all[start:end],
+		})
+	}
+	return waves, nil
+}
+
+// Targets renders the Fleet targets for a rollout at wave current. Clusters of waves up to
+// current get next; all other selected clusters keep previous. A nil previous leaves them undeployed.
+func Targets(selector metav1.LabelSelector, waves []Wave, current int, next, previous *fleetutil.BundleDeploymentOptions) []fleetutil.Target {
+	var targets []fleetutil.Target
+	for i := 0; i <= current && i < len(waves); i++ {
+		wave := waves[i]
+		if wave.Selector != nil {
+			targets = append(targets, fleetutil.Target{
+				ClusterSelector:         wave.Selector,
+				BundleDeploymentOptions: next,
+			})
+			continue
+		}
+		for _, name := range wave.Clusters {
+			targets = append(targets, fleetutil.Target{
+				ClusterName:             name,
+				BundleDeploymentOptions: next,
+			})
+		}
+	}
+	if previous != nil {
+		targets = append(targets, fleetutil.Target{
+			ClusterSelector:         &selector,
+			BundleDeploymentOptions: previous,
+		})
+	}
+	return targets
+}
+
+// Healthy reports whether every cluster of the waves up to current is ready on the expected release
+func Healthy(waves []Wave, current int, expected drift.Expected, deployments []fleetutil.BundleDeploymentState) bool {
+	byCluster := map[string]fleetutil.BundleDeploymentState{}
+	for _, bd := range deployments {
+		byCluster[bd.Cluster] = bd
+	}
+
+	var clusters []string
+	var reached []fleetutil.BundleDeploymentState
+	for i := 0; i <= current && i < len(waves); i++ {
+		for _, name := range waves[i].Clusters {
+			bd, ok := byCluster[name]
+			if !ok || !bd.Ready || bd.Failed() {
+				return false
+			}
+			clusters = append(clusters, name)
+			reached = append(reached, bd)
+		}
+	}
+	return len(drift.Detect(expected, clusters, reached)) == 0
+}
+
+// Select returns the names, sorted, of the clusters matched by selector
+func Select(selector metav1.LabelSelector, clusters []Cluster) ([]string, error) {
+	matched, err := matching(selector, clusters)
+	if err != nil {
+		return nil, err
+	}
+	return names(matched), nil
+}
+
+func matching(selector metav1.LabelSelector, clusters []Cluster) ([]Cluster, error) {
+	s, err := metav1.LabelSelectorAsSelector(&selector)
+	if err != nil {
+		return nil, err
+	}
+	var out []Cluster
+	for _, c := range clusters {
+		if s.Matches(labels.Set(c.Labels)) {
+			out = append(out, c)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+func names(clusters []Cluster) []string {
+	out := make([]string, 0, len(clusters))
+	for _, c := range clusters {
+		out = append(out, c.Name)
+	}
+	return out
+}
@@ -0,0 +1,145 @@
+package rollout
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	multisuseiov1alpha1 "github.com/suse/rancher-multi-compute/api/multi.suse.io/v1alpha1"
+	"github.com/suse/rancher-multi-compute/internal/drift"
+	"github.com/suse/rancher-multi-compute/internal/fleetutil"
+)
+
+var (
+	gpuSelector = metav1.LabelSelector{MatchLabels: map[string]string{"gpu": "nvidia"}}
+
+	testClusters = []Cluster{
+		{Name: "prod-b", Labels: map[string]string{"gpu": "nvidia", "env": "prod"}},
+		{Name: "staging-a", Labels: map[string]string{"gpu": "nvidia", "env": "staging"}},
+		{Name: "prod-a", Labels: map[string]string{"gpu": "nvidia", "env": "prod"}},
+		{Name: "edge-a", Labels: map[string]string{"gpu": "nvidia", "env": "edge"}},
+		{Name: "cpu-a", Labels: map[string]string{"env": "prod"}},
+	}
+)
+
+func TestPlan_NoStrategy(t *testing.T) {
+	waves, err := Plan(gpuSelector, nil, testClusters)
+	require.NoError(t, err)
+
+	require.Len(t, waves, 1)
+	assert.Equal(t, []string{"edge-a", "prod-a", "prod-b", "staging-a"}, waves[0].Clusters)
+}
+
+func TestPlan_Waves(t *testing.T) {
+	strategy := &multisuseiov1alpha1.RolloutStrategy{
+		Waves: []multisuseiov1alpha1.RolloutWave{
+			{Name: "staging", ClusterSelector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "staging"}}},
+			{Name: "prod", ClusterSelector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}}},
+		},
+	}
+
+	waves, err := Plan(gpuSelector, strategy, testClusters)
+	require.NoError(t, err)
+
+	require.Len(t, waves, 3)
+	assert.Equal(t, "staging", waves[0].Name)
+	assert.Equal(t, []string{"staging-a"}, waves[0].Clusters)
+	assert.Equal(t, "prod", waves[1].Name)
+	assert.Equal(t, []string{"prod-a", "prod-b"}, waves[1].Clusters)
+	assert.Equal(t, "remaining", waves[2].Name)
+	assert.Equal(t, []string{"edge-a"}, waves[2].Clusters)
+	assert.Equal(t, &gpuSelector, waves[2].Selector)
+}
+
+func TestPlan_MaxUnavailable(t *testing.T) {
+	maxUnavailable := intstr.FromString("50%")
+	strategy := &multisuseiov1alpha1.RolloutStrategy{MaxUnavailable: &maxUnavailable}
+
+	waves, err := Plan(gpuSelector, strategy, testClusters)
+	require.NoError(t, err)
+
+	require.Len(t, waves, 2)
+	assert.Equal(t, "batch-1", waves[0].Name)
+	assert.Nil(t, waves[0].Selector)
+	assert.Equal(t, []string{"edge-a", "prod-a"}, waves[0].Clusters)
+	assert.Equal(t, []string{"prod-b", "staging-a"}, waves[1].Clusters)
+}
+
+func TestPlan_MaxUnavailableRoundsUpToOne(t *testing.T) {
+	maxUnavailable := intstr.FromString("1%")
+	strategy := &multisuseiov1alpha1.RolloutStrategy{MaxUnavailable: &maxUnavailable}
+
+	waves, err := Plan(gpuSelector, strategy, testClusters)
+	require.NoError(t, err)
+	assert.Len(t, waves, 4)
+}
+
+func TestSelect(t *testing.T) {
+	selected, err := Select(gpuSelector, testClusters)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"edge-a", "prod-a", "prod-b", "staging-a"}, selected)
+
+	_, err = Select(metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+		{Key: "gpu", Operator: "Bogus"},
+	}}, testClusters)
+	assert.Error(t, err)
+}
+
+func TestTargets(t *testing.T) {
+	next := &fleetutil.BundleDeploymentOptions{DefaultNamespace: "next"}
+	previous := &fleetutil.BundleDeploymentOptions{DefaultNamespace: "previous"}
+	staging := metav1.LabelSelector{MatchLabels: map[string]string{"env": "staging"}}
+	waves := []Wave{
+		{Name: "staging", Selector: &staging, Clusters: []string{"staging-a"}},
+		{Name: "batch-2", Clusters: []string{"prod-a", "prod-b"}},
+	}
+
+	targets := Targets(gpuSelector, waves, 1, next, previous)
+
+	require.Len(t, targets, 4)
+	assert.Equal(t, &staging, targets[0].ClusterSelector)
+	assert.Equal(t, next, targets[0].BundleDeploymentOptions)
+	assert.Equal(t, "prod-a", targets[1].ClusterName)
+	assert.Equal(t, "prod-b", targets[2].ClusterName)
+	assert.Equal(t, &gpuSelector, targets[3].ClusterSelector)
+	assert.Equal(t, previous, targets[3].BundleDeploymentOptions)
+
+	// First install: clusters of later waves are not deployed yet
+	targets = Targets(gpuSelector, waves, 0, next, nil)
+	require.Len(t, targets, 1)
+	assert.Equal(t, next, targets[0].BundleDeploymentOptions)
+}
+
+func TestHealthy(t *testing.T) {
+	expected := drift.Expected{Values: map[string]interface{}{"image": map[string]interface{}{"operatorTag": "v25.0.0"}}}
+	deployed := func(cluster, tag string, ready bool) fleetutil.BundleDeploymentState {
+		return fleetutil.BundleDeploymentState{
+			Cluster: cluster,
+			Ready:   ready,
+			Helm: fleetutil.HelmOptions{
+				Values: map[string]interface{}{"image": map[string]interface{}{"operatorTag": tag}},
+			},
+		}
+	}
+	waves := []Wave{
+		{Name: "staging", Clusters: []string{"staging-a"}},
+		{Name: "prod", Clusters: []string{"prod-a"}},
+	}
+
+	assert.True(t, Healthy(waves, 0, expected, []fleetutil.BundleDeploymentState{
+		deployed("staging-a", "v25.0.0", true),
+		deployed("prod-a", "v24.9.0", true),
+	}))
+	assert.False(t, Healthy(waves, 1, expected, []fleetutil.BundleDeploymentState{
+		deployed("staging-a", "v25.0.0", true),
+		deployed("prod-a", "v24.9.0", true),
+	}), "prod-a still runs the previous version")
+	assert.False(t, Healthy(waves, 0, expected, []fleetutil.BundleDeploymentState{
+		deployed("staging-a", "v25.0.0", false),
+	}), "staging-a is not ready")
+	assert.False(t, Healthy(waves, 0, expected, nil), "staging-a has no deployment")
+	assert.True(t, Healthy([]Wave{{Name: "empty"}}, 0, expected, nil))
+}
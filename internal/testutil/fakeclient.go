@@ -0,0 +1,28 @@
+// Package testutil holds helpers shared by the unit tests of the controllers
+package testutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	multisuseiov1alpha1 "github.com/suse/rancher-multi-compute/api/multi.suse.io/v1alpha1"
+)
+
+// NewFakeClient returns a fake client holding objects. The core and multi.suse.io kinds are registered
+// with the status subresource of the latter; Fleet objects are stored as unstructured.
+func NewFakeClient(t *testing.T, objects ...client.Object) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, multisuseiov1alpha1.AddToScheme(scheme))
+	return fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objects...).
+		WithStatusSubresource(&multisuseiov1alpha1.Channel{}, &multisuseiov1alpha1.MultiComputeConfig{}).
+		Build()
+}
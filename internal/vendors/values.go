@@ -0,0 +1,67 @@
+package vendors
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/suse/rancher-multi-compute/internal/versions"
+)
+
+// Default values templates mapping pins to the keys of the built-in vendor charts. The NVIDIA runtime
+// pin is the version of the NVIDIA Container Toolkit the GPU operator installs; the driver is left to
+// the operator's default.
+const (
+	nvidiaValuesTemplate = `operator:
+  version: {{ quote .OperatorTag }}
+toolkit:
+  version: {{ quote .RuntimeTag }}
+`
+	amdValuesTemplate = `image:
+  tag: {{ quote .OperatorTag }}
+`
+	intelValuesTemplate = `image:
+  tag: {{ quote .OperatorTag }}
+`
+)
+
+// valuesFuncs are the functions available to values templates
+var valuesFuncs = template.FuncMap{
+	"quote": strconv.Quote,
+}
+
+// parseValuesTemplate parses a values template, a Go template rendering Helm values as YAML
+func parseValuesTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("values").Funcs(valuesFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid values template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// HelmValues renders the Helm values installing the vendor chart with the given pins. The values
+// template is executed with the pins (.OperatorTag, .RuntimeTag, .ChartVersion); a source without
+// template gets the generic image values of the pins.
+func (s Source) HelmValues(pins versions.Pins) (map[string]interface{}, error) {
+	if s.ValuesTemplate == "" {
+		return pins.HelmValues(), nil
+	}
+
+	tmpl, err := parseValuesTemplate(s.ValuesTemplate)
+	if err != nil {
+		return nil, err
+	}
+	var rendered bytes.Buffer
+	if err := tmpl.Execute(&rendered, pins); err != nil {
+		return nil, fmt.Errorf("failed to render values template: %w", err)
+	}
+
+	values := map[string]interface{}{}
+	if err := yaml.Unmarshal(rendered.Bytes(), &values); err != nil {
+		return nil, fmt.Errorf("values template did not render a YAML map: %w", err)
+	}
+	return values, nil
+}
@@ -0,0 +1,66 @@
+package vendors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/suse/rancher-multi-compute/internal/versions"
+)
+
+func TestSource_HelmValues_Defaults(t *testing.T) {
+	pins := versions.Pins{OperatorTag: "v24.9.0", RuntimeTag: "v1.17.0"}
+	sources := DefaultSources()
+
+	values, err := sources[VendorNVIDIA].HelmValues(pins)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"operator": map[string]interface{}{"version": "v24.9.0"},
+		"toolkit":  map[string]interface{}{"version": "v1.17.0"},
+	}, values)
+
+	for _, vendor := range []Vendor{VendorAMD, VendorIntel} {
+		values, err := sources[vendor].HelmValues(pins)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"image": map[string]interface{}{"tag": "v24.9.0"}}, values, vendor)
+	}
+}
+
+func TestSource_HelmValues_Template(t *testing.T) {
+	source := Source{ValuesTemplate: `
+image:
+  tag: {{ quote .OperatorTag }}
+runtime:
+  version: {{ quote .RuntimeTag }}
+{{- if .ChartVersion }}
+chartVersion: {{ quote .ChartVersion }}
+{{- end }}
+`}
+
+	values, err := source.HelmValues(versions.Pins{OperatorTag: "1.19.0", RuntimeTag: "1.19"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"image":   map[string]interface{}{"tag": "1.19.0"},
+		"runtime": map[string]interface{}{"version": "1.19"},
+	}, values)
+
+	// Without template the generic image values are rendered
+	values, err = Source{}.HelmValues(versions.Pins{OperatorTag: "v1", RuntimeTag: "r1"})
+	require.NoError(t, err)
+	assert.Equal(t, versions.Pins{OperatorTag: "v1", RuntimeTag: "r1"}.HelmValues(), values)
+}
+
+func TestSource_HelmValues_Invalid(t *testing.T) {
+	_, err := Source{ValuesTemplate: "image: {{ .OperatorTag"}.HelmValues(versions.Pins{})
+	assert.ErrorContains(t, err, "invalid values template")
+
+	_, err = Source{ValuesTemplate: "{{ .DriverTag }}"}.HelmValues(versions.Pins{})
+	assert.ErrorContains(t, err, "failed to render values template")
+
+	_, err = Source{ValuesTemplate: "- {{ .OperatorTag }}"}.HelmValues(versions.Pins{OperatorTag: "v1"})
+	assert.ErrorContains(t, err, "did not render a YAML map")
+
+	err = Source{Repo: "https://charts.example.internal", Chart: "c", ValuesTemplate: "{{ end }}"}.Validate()
+	assert.ErrorContains(t, err, "invalid values template")
+}
@@ -1,5 +1,20 @@
 package vendors
 
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/suse/rancher-multi-compute/internal/fleetutil"
+)
+
 // Vendor represents a GPU vendor
 type Vendor string
 
@@ -9,30 +24,127 @@ const (
 	VendorIntel  Vendor = "intel"
 )
 
+// vendorName matches vendor names usable as version pin keys and in resource names
+var vendorName = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
 // Source represents a vendor's Helm chart source
 type Source struct {
-	Repo      string `json:"repo"`
-	Chart     string `json:"chart"`
-	Namespace string `json:"namespace"`
+	Repo      string `json:"repo" yaml:"repo"`
+	Chart     string `json:"chart" yaml:"chart"`
+	Namespace string `json:"namespace" yaml:"namespace"`
+	// HelmSecretName is a Secret in the Fleet namespace holding the repository credentials
+	HelmSecretName string `json:"helmSecretName,omitempty" yaml:"helmSecretName,omitempty"`
+	// CABundle is a PEM bundle of the CAs verifying the repository certificate
+	CABundle              string `json:"caBundle,omitempty" yaml:"caBundle,omitempty"`
+	InsecureSkipTLSVerify bool   `json:"insecureSkipTLSVerify,omitempty" yaml:"insecureSkipTLSVerify,omitempty"`
+	// ValuesTemplate maps the pins to the Helm values of the chart, see HelmValues
+	ValuesTemplate string `json:"valuesTemplate,omitempty" yaml:"valuesTemplate,omitempty"`
+}
+
+// Validate checks the chart reference and the values template. An oci:// repository must form a
+// valid OCI reference with the chart.
+func (s Source) Validate() error {
+	if _, err := fleetutil.ChartOptions(s.Repo, s.Chart); err != nil {
+		return err
+	}
+	if s.ValuesTemplate != "" {
+		if _, err := parseValuesTemplate(s.ValuesTemplate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Authenticated reports whether pulling the chart needs credentials or a CA bundle
+func (s Source) Authenticated() bool {
+	return s.HelmSecretName != "" || s.CABundle != ""
 }
 
+// Registry maps the vendors known to the controllers to their chart source
+type Registry map[Vendor]Source
+
 // DefaultSources returns the default vendor sources
 func DefaultSources() map[Vendor]Source {
 	return map[Vendor]Source{
 		VendorNVIDIA: {
-			Repo:      "https://nvidia.github.io/helm-charts",
-			Chart:     "gpu-operator",
-			Namespace: "gpu-operator",
+			Repo:           "https://nvidia.github.io/helm-charts",
+			Chart:          "gpu-operator",
+			Namespace:      "gpu-operator",
+			ValuesTemplate: nvidiaValuesTemplate,
 		},
 		VendorAMD: {
-			Repo:      "https://rocm.github.io/helm-charts",
-			Chart:     "rocm-device-plugin",
-			Namespace: "rocm-system",
+			Repo:           "https://rocm.github.io/helm-charts",
+			Chart:          "rocm-device-plugin",
+			Namespace:      "rocm-system",
+			ValuesTemplate: amdValuesTemplate,
 		},
 		VendorIntel: {
-			Repo:      "https://intel.github.io/helm-charts",
-			Chart:     "intel-gpu-plugin",
-			Namespace: "intel-gpu",
+			Repo:           "https://intel.github.io/helm-charts",
+			Chart:          "intel-gpu-plugin",
+			Namespace:      "intel-gpu",
+			ValuesTemplate: intelValuesTemplate,
 		},
 	}
 }
+
+// DefaultRegistry returns a registry of the built-in vendors
+func DefaultRegistry() Registry {
+	return Registry(DefaultSources())
+}
+
+// Lookup returns the source of a vendor, matching its name case-insensitively
+func (r Registry) Lookup(name string) (Vendor, Source, bool) {
+	vendor := Vendor(strings.ToLower(name))
+	source, ok := r[vendor]
+	return vendor, source, ok
+}
+
+// Names returns the sorted vendor names of the registry
+func (r Registry) Names() []string {
+	names := make([]string, 0, len(r))
+	for vendor := range r {
+		names = append(names, string(vendor))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadRegistry parses vendor definitions, a YAML map of vendor name to source, and adds them
+// to the built-in vendors. A definition named after a built-in vendor replaces it, keeping its
+// values template unless it sets one.
+func LoadRegistry(data []byte) (Registry, error) {
+	var definitions map[string]Source
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&definitions); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to unmarshal vendor definitions: %w", err)
+	}
+
+	registry := DefaultRegistry()
+	for name, source := range definitions {
+		if !vendorName.MatchString(name) {
+			return nil, fmt.Errorf("invalid vendor name %q: must be a lowercase DNS label", name)
+		}
+		if source.Repo == "" || source.Chart == "" || source.Namespace == "" {
+			return nil, fmt.Errorf("vendor %s: repo, chart and namespace are required", name)
+		}
+		if err := source.Validate(); err != nil {
+			return nil, fmt.Errorf("vendor %s: %w", name, err)
+		}
+		if source.ValuesTemplate == "" {
+			// A replaced built-in vendor keeps mapping the pins to its chart
+			source.ValuesTemplate = registry[Vendor(name)].ValuesTemplate
+		}
+		registry[Vendor(name)] = source
+	}
+	return registry, nil
+}
+
+// LoadRegistryFile loads vendor definitions from a file, see LoadRegistry
+func LoadRegistryFile(path string) (Registry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vendor definitions %s: %w", path, err)
+	}
+	return LoadRegistry(data)
+}
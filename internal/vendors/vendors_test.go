@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDefaultSources(t *testing.T) {
@@ -38,3 +39,63 @@ func TestVendorString(t *testing.T) {
 	assert.Equal(t, "amd", string(VendorAMD))
 	assert.Equal(t, "intel", string(VendorIntel))
 }
+
+func TestLoadRegistry(t *testing.T) {
+	registry, err := LoadRegistry([]byte(`
+habana:
+  repo: https://vault.habana.ai/artifactory/api/helm/gaudi-helm
+  chart: habana-ai-operator
+  namespace: habana-ai-operator
+nvidia:
+  repo: https://charts.example.internal/nvidia
+  chart: gpu-operator
+  namespace: gpu-operator
+  helmSecretName: chart-mirror
+  insecureSkipTLSVerify: true
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"amd", "habana", "intel", "nvidia"}, registry.Names())
+	assert.Equal(t, "https://charts.example.internal/nvidia", registry[VendorNVIDIA].Repo)
+	assert.True(t, registry[VendorNVIDIA].Authenticated())
+	assert.True(t, registry[VendorNVIDIA].InsecureSkipTLSVerify)
+	assert.Equal(t, nvidiaValuesTemplate, registry[VendorNVIDIA].ValuesTemplate, "a replaced vendor keeps its values template")
+	assert.Empty(t, registry["habana"].ValuesTemplate)
+	assert.False(t, registry[VendorAMD].Authenticated())
+
+	vendor, source, ok := registry.Lookup("Habana")
+	assert.True(t, ok)
+	assert.Equal(t, Vendor("habana"), vendor)
+	assert.Equal(t, "habana-ai-operator", source.Chart)
+
+	_, _, ok = registry.Lookup("neuron")
+	assert.False(t, ok)
+}
+
+func TestLoadRegistry_Invalid(t *testing.T) {
+	_, err := LoadRegistry([]byte("Neuron_SDK:\n  repo: r\n  chart: c\n  namespace: n\n"))
+	assert.ErrorContains(t, err, "invalid vendor name")
+
+	_, err = LoadRegistry([]byte("neuron:\n  repo: r\n  chart: c\n"))
+	assert.ErrorContains(t, err, "vendor neuron: repo, chart and namespace are required")
+
+	_, err = LoadRegistry([]byte("neuron:\n  repo: oci://public.ecr.aws\n  chart: Neuron\n  namespace: n\n"))
+	assert.ErrorContains(t, err, "vendor neuron: invalid OCI reference")
+
+	_, err = LoadRegistry([]byte("neuron:\n  repository: r\n"))
+	assert.ErrorContains(t, err, "failed to unmarshal vendor definitions")
+
+	registry, err := LoadRegistry(nil)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultRegistry(), registry)
+}
+
+func TestLoadRegistry_OCI(t *testing.T) {
+	registry, err := LoadRegistry([]byte(`
+neuron:
+  repo: oci://public.ecr.aws/neuron
+  chart: neuron-helm-chart:1.1.0
+  namespace: neuron-system
+`))
+	require.NoError(t, err)
+	assert.NoError(t, registry["neuron"].Validate())
+}
@@ -0,0 +1,58 @@
+package versions
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// DefaultConfigMapNamespace is the namespace holding the version ConfigMaps
+	DefaultConfigMapNamespace = "cattle-fleet-system"
+	// ChannelLabel names the release channel whose pins a version ConfigMap carries
+	ChannelLabel = "multi.suse.io/channel"
+)
+
+// ConfigMapResolver resolves versions from the ConfigMaps labeled with their release channel.
+// Backed by a cached client, lookups are served from informers.
+type ConfigMapResolver struct {
+	Reader    client.Reader
+	Namespace string
+}
+
+// NewConfigMapResolver creates a new ConfigMapResolver
+func NewConfigMapResolver(reader client.Reader, namespace string) *ConfigMapResolver {
+	return &ConfigMapResolver{
+		Reader:    reader,
+		Namespace: namespace,
+	}
+}
+
+// Resolve resolves versions for a given channel
+func (r *ConfigMapResolver) Resolve(ctx context.Context, channel string) (VendorPins, error) {
+	configMaps := &corev1.ConfigMapList{}
+	if err := r.Reader.List(ctx, configMaps,
+		client.InNamespace(r.Namespace),
+		client.MatchingLabels{ChannelLabel: channel},
+	); err != nil {
+		return nil, fmt.Errorf("failed to list version ConfigMaps: %w", err)
+	}
+
+	switch len(configMaps.Items) {
+	case 0:
+		return nil, fmt.Errorf("no ConfigMap labeled %s=%s in namespace %s", ChannelLabel, channel, r.Namespace)
+	case 1:
+	default:
+		return nil, fmt.Errorf("%d ConfigMaps labeled %s=%s in namespace %s, expected one",
+			len(configMaps.Items), ChannelLabel, channel, r.Namespace)
+	}
+
+	cm := configMaps.Items[0]
+	pins, err := ParseConfigMapData(cm.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version ConfigMap %s/%s: %w", cm.Namespace, cm.Name, err)
+	}
+	return pins, nil
+}
@@ -0,0 +1,68 @@
+package versions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func versionConfigMap(name, channel string, data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: DefaultConfigMapNamespace,
+			Labels:    map[string]string{ChannelLabel: channel},
+		},
+		Data: data,
+	}
+}
+
+func TestConfigMapResolver_Resolve(t *testing.T) {
+	reader := fake.NewClientBuilder().WithObjects(
+		versionConfigMap("version-config-stable", "stable", map[string]string{
+			"nvidia": "operatorTag: v24.9.0\nruntimeTag: 12.4.1\n",
+			"amd":    "operatorTag: v1.0.0\nruntimeTag: 5.7.1\n",
+			"intel":  "operatorTag: v0.4.0\nruntimeTag: 23.3.0\n",
+		}),
+		versionConfigMap("version-config-canary", "canary", map[string]string{
+			"nvidia": "operatorTag: v25.0.0-rc1\nruntimeTag: 12.5.0-rc1\n",
+			"amd":    "runtimeTag: 6.0.0\n",
+		}),
+	).Build()
+	resolver := NewConfigMapResolver(reader, DefaultConfigMapNamespace)
+
+	pins, err := resolver.Resolve(context.Background(), "stable")
+	require.NoError(t, err)
+	assert.Equal(t, Pins{OperatorTag: "v24.9.0", RuntimeTag: "12.4.1"}, pins["nvidia"])
+	assert.Equal(t, "5.7.1", pins["amd"].RuntimeTag)
+
+	_, err = resolver.Resolve(context.Background(), "canary")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "version-config-canary")
+	assert.Contains(t, err.Error(), "amd.operatorTag")
+
+	_, err = resolver.Resolve(context.Background(), "lts")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "multi.suse.io/channel=lts")
+}
+
+func TestConfigMapResolver_Resolve_Ambiguous(t *testing.T) {
+	data := map[string]string{
+		"VERSION.yaml": "nvidia: {operatorTag: a, runtimeTag: b}\namd: {operatorTag: a, runtimeTag: b}\nintel: {operatorTag: a, runtimeTag: b}\n",
+	}
+	objects := []client.Object{
+		versionConfigMap("version-config-stable", "stable", data),
+		versionConfigMap("version-config-stable-copy", "stable", data),
+	}
+	resolver := NewConfigMapResolver(fake.NewClientBuilder().WithObjects(objects...).Build(), DefaultConfigMapNamespace)
+
+	_, err := resolver.Resolve(context.Background(), "stable")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected one")
+}
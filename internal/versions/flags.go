@@ -0,0 +1,74 @@
+package versions
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ResolverFlags are the command-line flags selecting where the controllers read version pins from.
+// At most one of Dir, Constraints and GitURL may be set; without any, pins are read from ConfigMaps.
+type ResolverFlags struct {
+	Dir         string
+	Constraints string
+	GitURL      string
+	GitBranch   string
+	GitPath     string
+}
+
+// BindFlags registers the version resolver flags on fs
+func (f *ResolverFlags) BindFlags(fs *flag.FlagSet) {
+	fs.StringVar(&f.Dir, "version-dir", "",
+		"Directory holding a VERSION.yaml per release channel. "+
+			"When empty, version pins are read from the ConfigMaps labeled "+ChannelLabel+".")
+	fs.StringVar(&f.Constraints, "version-constraints", "",
+		"File mapping each release channel to semver constraints resolved against Helm repositories and OCI registries.")
+	fs.StringVar(&f.GitURL, "version-git-url", "",
+		"Git repository holding a VERSION.yaml per release channel, tracked at --version-git-branch.")
+	fs.StringVar(&f.GitBranch, "version-git-branch", "main", "Branch of --version-git-url to read version pins from.")
+	fs.StringVar(&f.GitPath, "version-git-path", "fleet/overlays",
+		"Directory of --version-git-url holding the release channel directories.")
+}
+
+// Validate rejects selecting more than one version resolver
+func (f ResolverFlags) Validate() error {
+	selected := 0
+	for _, value := range []string{f.Dir, f.Constraints, f.GitURL} {
+		if value != "" {
+			selected++
+		}
+	}
+	if selected > 1 {
+		return errors.New("--version-dir, --version-constraints and --version-git-url are mutually exclusive")
+	}
+	return nil
+}
+
+// NewResolver returns the resolver the flags select, a ConfigMapResolver reading the version ConfigMaps
+// from reader unless another one is set
+func (f ResolverFlags) NewResolver(reader client.Reader) (Resolver, error) {
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+	switch {
+	case f.Dir != "":
+		return NewFileResolver(f.Dir), nil
+	case f.GitURL != "":
+		return NewGitResolver(f.GitURL, f.GitBranch, f.GitPath), nil
+	case f.Constraints != "":
+		data, err := os.ReadFile(f.Constraints)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read version constraints %s: %w", f.Constraints, err)
+		}
+		constraints, err := LoadConstraints(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version constraints %s: %w", f.Constraints, err)
+		}
+		return NewSemverResolver(constraints), nil
+	default:
+		return NewConfigMapResolver(reader, DefaultConfigMapNamespace), nil
+	}
+}
@@ -0,0 +1,55 @@
+package versions
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolverFlags_Validate(t *testing.T) {
+	fs := flag.NewFlagSet("controller", flag.ContinueOnError)
+	var flags ResolverFlags
+	flags.BindFlags(fs)
+	require.NoError(t, fs.Parse([]string{"--version-dir", "/versions"}))
+	assert.NoError(t, flags.Validate())
+	assert.Equal(t, "main", flags.GitBranch)
+	assert.Equal(t, "fleet/overlays", flags.GitPath)
+
+	require.NoError(t, fs.Parse([]string{"--version-git-url", "https://git.example.com/versions.git"}))
+	assert.EqualError(t, flags.Validate(), "--version-dir, --version-constraints and --version-git-url are mutually exclusive")
+	_, err := flags.NewResolver(nil)
+	assert.Error(t, err)
+}
+
+func TestResolverFlags_NewResolver(t *testing.T) {
+	resolver, err := ResolverFlags{}.NewResolver(nil)
+	require.NoError(t, err)
+	assert.IsType(t, &ConfigMapResolver{}, resolver)
+
+	resolver, err = ResolverFlags{Dir: "/versions"}.NewResolver(nil)
+	require.NoError(t, err)
+	assert.Equal(t, NewFileResolver("/versions"), resolver)
+
+	resolver, err = ResolverFlags{GitURL: "https://git.example.com/versions.git", GitBranch: "main", GitPath: "fleet/overlays"}.NewResolver(nil)
+	require.NoError(t, err)
+	assert.IsType(t, &GitResolver{}, resolver)
+
+	constraints := filepath.Join(t.TempDir(), "constraints.yaml")
+	require.NoError(t, os.WriteFile(constraints, []byte(`stable:
+  nvidia:
+    operator:
+      repo: https://helm.ngc.nvidia.com/nvidia
+      chart: gpu-operator
+      constraint: "~24.9"
+`), 0o600))
+	resolver, err = ResolverFlags{Constraints: constraints}.NewResolver(nil)
+	require.NoError(t, err)
+	assert.IsType(t, &SemverResolver{}, resolver)
+
+	_, err = ResolverFlags{Constraints: filepath.Join(t.TempDir(), "missing.yaml")}.NewResolver(nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,119 @@
+package versions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// DefaultGitRefreshInterval is how often the Git repository is fetched
+const DefaultGitRefreshInterval = time.Minute
+
+// RevisionResolver is a Resolver reporting the revision of its source the pins were read from
+type RevisionResolver interface {
+	Resolver
+	ResolveRevision(ctx context.Context, channel string) (VendorPins, string, error)
+}
+
+// GitResolver resolves versions from the <channel>/VERSION.yaml files of a branch of a Git
+// repository. Only the head of the branch is cloned, in memory, and fetched at most once per
+// RefreshInterval: the history of the pins is never read.
+type GitResolver struct {
+	URL    string
+	Branch string
+	// Path is the directory of the repository holding the channel directories
+	Path            string
+	RefreshInterval time.Duration
+
+	mu      sync.Mutex
+	repo    *git.Repository
+	fetched time.Time
+}
+
+// NewGitResolver creates a new GitResolver
+func NewGitResolver(url, branch, path string) *GitResolver {
+	return &GitResolver{
+		URL:             url,
+		Branch:          branch,
+		Path:            path,
+		RefreshInterval: DefaultGitRefreshInterval,
+	}
+}
+
+// Resolve resolves versions for a given channel
+func (r *GitResolver) Resolve(ctx context.Context, channel string) (VendorPins, error) {
+	pins, _, err := r.ResolveRevision(ctx, channel)
+	return pins, err
+}
+
+// ResolveRevision resolves versions for a given channel and returns the commit SHA they were read from
+func (r *GitResolver) ResolveRevision(ctx context.Context, channel string) (VendorPins, string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.sync(ctx); err != nil {
+		return nil, "", err
+	}
+	ref, err := r.repo.Reference(plumbing.NewRemoteReferenceName(git.DefaultRemoteName, r.Branch), true)
+	if err != nil {
+		return nil, "", fmt.Errorf("branch %s not found in %s: %w", r.Branch, r.URL, err)
+	}
+	commit, err := r.repo.CommitObject(ref.Hash())
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read commit %s: %w", ref.Hash(), err)
+	}
+
+	versionFile := path.Join(r.Path, channel, "VERSION.yaml")
+	file, err := commit.File(versionFile)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read %s at %s: %w", versionFile, ref.Hash(), err)
+	}
+	contents, err := file.Contents()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read %s at %s: %w", versionFile, ref.Hash(), err)
+	}
+
+	pins, err := ParseVersionFile([]byte(contents))
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid version file %s at %s: %w", versionFile, ref.Hash(), err)
+	}
+	return pins, ref.Hash().String(), nil
+}
+
+// sync shallow clones the branch on first use and fetches its head once RefreshInterval elapsed
+func (r *GitResolver) sync(ctx context.Context) error {
+	if r.repo == nil {
+		repo, err := git.CloneContext(ctx, memory.NewStorage(), nil, &git.CloneOptions{
+			URL:           r.URL,
+			ReferenceName: plumbing.NewBranchReferenceName(r.Branch),
+			SingleBranch:  true,
+			Depth:         1,
+			NoCheckout:    true,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to clone %s: %w", r.URL, err)
+		}
+		r.repo = repo
+		r.fetched = time.Now()
+		return nil
+	}
+	if time.Since(r.fetched) < r.RefreshInterval {
+		return nil
+	}
+
+	refSpec := config.RefSpec(fmt.Sprintf("+refs/heads/%s:refs/remotes/%s/%s", r.Branch, git.DefaultRemoteName, r.Branch))
+	err := r.repo.FetchContext(ctx, &git.FetchOptions{RefSpecs: []config.RefSpec{refSpec}, Depth: 1, Force: true})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return fmt.Errorf("failed to fetch %s: %w", r.URL, err)
+	}
+	r.fetched = time.Now()
+	return nil
+}
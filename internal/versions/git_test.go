@@ -0,0 +1,136 @@
+package versions
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const gitVersionFile = `nvidia:
+  operatorTag: "%s"
+  runtimeTag: "12.4.1"
+amd:
+  operatorTag: "v1.0.0"
+  runtimeTag: "5.7.1"
+intel:
+  operatorTag: "v0.4.0"
+  runtimeTag: "23.3.0"
+`
+
+// pinRepo is a work tree pushing pin changes to a local bare repository
+type pinRepo struct {
+	t    *testing.T
+	dir  string
+	work *git.Repository
+	bare string
+}
+
+func newPinRepo(t *testing.T) *pinRepo {
+	dir := t.TempDir()
+	work, err := git.PlainInitWithOptions(dir, &git.PlainInitOptions{
+		InitOptions: git.InitOptions{DefaultBranch: "refs/heads/main"},
+	})
+	require.NoError(t, err)
+	return &pinRepo{t: t, dir: dir, work: work, bare: filepath.Join(t.TempDir(), "pins.git")}
+}
+
+// commit writes the NVIDIA operator tag of a channel and returns the commit SHA
+func (p *pinRepo) commit(channel, operatorTag string) string {
+	file := filepath.Join("fleet", "overlays", channel, "VERSION.yaml")
+	require.NoError(p.t, os.MkdirAll(filepath.Join(p.dir, filepath.Dir(file)), 0755))
+	content := []byte(fmt.Sprintf(gitVersionFile, operatorTag))
+	require.NoError(p.t, os.WriteFile(filepath.Join(p.dir, file), content, 0644))
+
+	tree, err := p.work.Worktree()
+	require.NoError(p.t, err)
+	_, err = tree.Add(file)
+	require.NoError(p.t, err)
+	hash, err := tree.Commit("Pin "+operatorTag, &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	})
+	require.NoError(p.t, err)
+	return hash.String()
+}
+
+// push publishes the work tree to the bare repository
+func (p *pinRepo) push() {
+	if _, err := os.Stat(p.bare); os.IsNotExist(err) {
+		_, err := git.PlainClone(p.bare, true, &git.CloneOptions{URL: p.dir})
+		require.NoError(p.t, err)
+		return
+	}
+	bare, err := git.PlainOpen(p.bare)
+	require.NoError(p.t, err)
+	err = bare.Fetch(&git.FetchOptions{RefSpecs: []config.RefSpec{"+refs/heads/*:refs/heads/*"}})
+	if err != git.NoErrAlreadyUpToDate {
+		require.NoError(p.t, err)
+	}
+}
+
+func TestGitResolver_ResolveRevision(t *testing.T) {
+	repo := newPinRepo(t)
+	first := repo.commit("stable", "v24.9.0")
+	repo.push()
+
+	resolver := NewGitResolver(repo.bare, "main", "fleet/overlays")
+	pins, revision, err := resolver.ResolveRevision(context.Background(), "stable")
+	require.NoError(t, err)
+	assert.Equal(t, first, revision)
+	assert.Equal(t, "v24.9.0", pins["nvidia"].OperatorTag)
+
+	second := repo.commit("stable", "v24.9.1")
+	repo.push()
+
+	// Within the refresh interval the fetched commit is kept
+	_, revision, err = resolver.ResolveRevision(context.Background(), "stable")
+	require.NoError(t, err)
+	assert.Equal(t, first, revision)
+
+	resolver.RefreshInterval = 0
+	pins, revision, err = resolver.ResolveRevision(context.Background(), "stable")
+	require.NoError(t, err)
+	assert.Equal(t, second, revision)
+	assert.Equal(t, "v24.9.1", pins["nvidia"].OperatorTag)
+}
+
+func TestGitResolver_ShallowClone(t *testing.T) {
+	repo := newPinRepo(t)
+	first := repo.commit("stable", "v24.9.0")
+	second := repo.commit("stable", "v24.9.1")
+	repo.push()
+
+	resolver := NewGitResolver(repo.bare, "main", "fleet/overlays")
+	_, revision, err := resolver.ResolveRevision(context.Background(), "stable")
+	require.NoError(t, err)
+	assert.Equal(t, second, revision)
+
+	// Only the head of the branch is cloned
+	_, err = resolver.repo.CommitObject(plumbing.NewHash(first))
+	assert.ErrorIs(t, err, plumbing.ErrObjectNotFound)
+}
+
+func TestGitResolver_Resolve_Errors(t *testing.T) {
+	repo := newPinRepo(t)
+	repo.commit("stable", "")
+	repo.push()
+
+	_, err := NewGitResolver(repo.bare, "main", "fleet/overlays").Resolve(context.Background(), "stable")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "nvidia.operatorTag")
+
+	_, err = NewGitResolver(repo.bare, "main", "fleet/overlays").Resolve(context.Background(), "lts")
+	assert.ErrorContains(t, err, "fleet/overlays/lts/VERSION.yaml")
+
+	_, err = NewGitResolver(repo.bare, "release", "fleet/overlays").Resolve(context.Background(), "stable")
+	assert.ErrorContains(t, err, "failed to clone")
+}
@@ -0,0 +1,351 @@
+package versions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// DefaultCacheTTL is how long the versions listed by a repository are reused
+	DefaultCacheTTL = 5 * time.Minute
+	// maxTagPages bounds the pages of an OCI tag list followed before giving up
+	maxTagPages = 20
+)
+
+// PinConstraint resolves a pin to the highest version of a repository matching a semver constraint
+type PinConstraint struct {
+	// Repo is a Helm repository (https://...) whose index.yaml lists Chart, or an OCI repository (oci://registry/path)
+	Repo string `yaml:"repo"`
+	// Chart is the chart looked up in a Helm repository index
+	Chart string `yaml:"chart,omitempty"`
+	// Constraint is the semver constraint, e.g. ~24.9 or >=25.0.0-0 to include pre-releases
+	Constraint string `yaml:"constraint"`
+}
+
+// VendorConstraints resolves the pins of a vendor, the chart version only when Chart is set
+type VendorConstraints struct {
+	Operator PinConstraint  `yaml:"operator"`
+	Runtime  PinConstraint  `yaml:"runtime"`
+	Chart    *PinConstraint `yaml:"chart,omitempty"`
+}
+
+// ChannelConstraints maps a release channel to the constraints of each vendor
+type ChannelConstraints map[string]map[string]VendorConstraints
+
+// LoadConstraints parses channel constraints from YAML
+func LoadConstraints(data []byte) (ChannelConstraints, error) {
+	var constraints ChannelConstraints
+	if err := yaml.Unmarshal(data, &constraints); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal version constraints: %w", err)
+	}
+	return constraints, nil
+}
+
+// SemverResolver resolves versions by matching semver constraints against the versions
+// published in Helm repository indexes and OCI tag lists
+type SemverResolver struct {
+	Channels   ChannelConstraints
+	HTTPClient *http.Client
+	CacheTTL   time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cachedVersions
+}
+
+// cachedVersions holds the versions listed by a repository, per chart for a Helm repository
+type cachedVersions struct {
+	versions map[string][]string
+	fetched  time.Time
+}
+
+// NewSemverResolver creates a new SemverResolver
+func NewSemverResolver(channels ChannelConstraints) *SemverResolver {
+	return &SemverResolver{
+		Channels:   channels,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+		CacheTTL:   DefaultCacheTTL,
+	}
+}
+
+// Resolve resolves versions for a given channel
+func (r *SemverResolver) Resolve(ctx context.Context, channel string) (VendorPins, error) {
+	vendors, ok := r.Channels[channel]
+	if !ok {
+		return nil, fmt.Errorf("no version constraints for channel %s", channel)
+	}
+
+	names := make([]string, 0, len(vendors))
+	for vendor := range vendors {
+		names = append(names, vendor)
+	}
+	sort.Strings(names)
+
+	pins := make(VendorPins, len(vendors))
+	for _, vendor := range names {
+		constraints := vendors[vendor]
+		operator, err := r.resolvePin(ctx, constraints.Operator)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s operator tag for channel %s: %w", vendor, channel, err)
+		}
+		runtime, err := r.resolvePin(ctx, constraints.Runtime)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s runtime tag for channel %s: %w", vendor, channel, err)
+		}
+		var chart string
+		if constraints.Chart != nil {
+			chart, err = r.resolvePin(ctx, *constraints.Chart)
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve %s chart version for channel %s: %w", vendor, channel, err)
+			}
+		}
+		pins[strings.ToLower(vendor)] = Pins{OperatorTag: operator, RuntimeTag: runtime, ChartVersion: chart}
+	}
+
+	if err := pins.Validate(); err != nil {
+		return nil, fmt.Errorf("incomplete version constraints for channel %s: %w", channel, err)
+	}
+	return pins, nil
+}
+
+// resolvePin returns the highest published version matching the constraint, as published
+func (r *SemverResolver) resolvePin(ctx context.Context, pin PinConstraint) (string, error) {
+	constraint, err := semver.NewConstraint(pin.Constraint)
+	if err != nil {
+		return "", fmt.Errorf("invalid constraint %q: %w", pin.Constraint, err)
+	}
+	published, err := r.versions(ctx, pin)
+	if err != nil {
+		return "", err
+	}
+	return highestMatching(constraint, published, pin.describe())
+}
+
+// highestMatching returns the highest of the published versions matching the constraint, as
+// published. Versions that are not semver, such as latest, are ignored.
+func highestMatching(constraint *semver.Constraints, published []string, source string) (string, error) {
+	var best *semver.Version
+	for _, raw := range published {
+		v, err := semver.NewVersion(raw)
+		if err != nil {
+			continue
+		}
+		if constraint.Check(v) && (best == nil || v.GreaterThan(best)) {
+			best = v
+		}
+	}
+	if best == nil {
+		return "", fmt.Errorf("no version of %s matches %s", source, constraint)
+	}
+	return best.Original(), nil
+}
+
+func (p PinConstraint) describe() string {
+	if p.Chart != "" {
+		return p.Repo + "/" + p.Chart
+	}
+	return p.Repo
+}
+
+// versions lists the versions published for the pin, caching each repository for CacheTTL
+func (r *SemverResolver) versions(ctx context.Context, pin PinConstraint) ([]string, error) {
+	oci := strings.HasPrefix(pin.Repo, "oci://")
+	r.mu.Lock()
+	cached, ok := r.cache[pin.Repo]
+	r.mu.Unlock()
+
+	if !ok || time.Since(cached.fetched) >= r.CacheTTL {
+		var listed map[string][]string
+		var err error
+		if oci {
+			var tags []string
+			tags, err = r.ociTags(ctx, pin.Repo)
+			listed = map[string][]string{"": tags}
+		} else {
+			listed, err = r.chartVersions(ctx, pin.Repo)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		cached = cachedVersions{versions: listed, fetched: time.Now()}
+		r.mu.Lock()
+		if r.cache == nil {
+			r.cache = map[string]cachedVersions{}
+		}
+		r.cache[pin.Repo] = cached
+		r.mu.Unlock()
+	}
+
+	if oci {
+		return cached.versions[""], nil
+	}
+	published, ok := cached.versions[pin.Chart]
+	if !ok {
+		return nil, fmt.Errorf("chart %s not found in %s", pin.Chart, pin.Repo)
+	}
+	return published, nil
+}
+
+// chartVersions lists the versions of every chart in a Helm repository index
+func (r *SemverResolver) chartVersions(ctx context.Context, repo string) (map[string][]string, error) {
+	indexURL := strings.TrimSuffix(repo, "/") + "/index.yaml"
+	resp, err := r.get(ctx, indexURL, "")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: %s", indexURL, resp.Status)
+	}
+
+	var index struct {
+		Entries map[string][]struct {
+			Version string `yaml:"version"`
+		} `yaml:"entries"`
+	}
+	if err := yaml.NewDecoder(resp.Body).Decode(&index); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", indexURL, err)
+	}
+	charts := make(map[string][]string, len(index.Entries))
+	for chart, entries := range index.Entries {
+		for _, entry := range entries {
+			charts[chart] = append(charts[chart], entry.Version)
+		}
+	}
+	return charts, nil
+}
+
+// linkNextPattern extracts the next page from a registry Link header
+var linkNextPattern = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// ociTags lists the tags of an OCI repository through the registry tag list API
+func (r *SemverResolver) ociTags(ctx context.Context, repo string) ([]string, error) {
+	registry, path, ok := strings.Cut(strings.TrimPrefix(repo, "oci://"), "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid OCI repository %s", repo)
+	}
+	base := &url.URL{Scheme: "https", Host: registry}
+	next := base.JoinPath("v2", path, "tags", "list").String()
+
+	var tags []string
+	var token string
+	for page := 0; next != "" && page < maxTagPages; page++ {
+		resp, err := r.getAuthorized(ctx, next, &token)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tags of %s: %w", repo, err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed to list tags of %s: %s", repo, resp.Status)
+		}
+
+		var list struct {
+			Tags []string `json:"tags"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&list)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse tags of %s: %w", repo, err)
+		}
+		tags = append(tags, list.Tags...)
+
+		next = ""
+		if m := linkNextPattern.FindStringSubmatch(resp.Header.Get("Link")); m != nil {
+			link, err := url.Parse(m[1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid tag list link %q: %w", m[1], err)
+			}
+			next = base.ResolveReference(link).String()
+		}
+	}
+	if next != "" {
+		// A truncated listing could miss the highest matching tag
+		return nil, fmt.Errorf("tag list of %s exceeds %d pages", repo, maxTagPages)
+	}
+	return tags, nil
+}
+
+// challengePattern extracts the parameters of a Bearer WWW-Authenticate challenge
+var challengePattern = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// anonymousToken requests a pull token from the realm of a Bearer challenge
+func (r *SemverResolver) anonymousToken(ctx context.Context, challenge string) (string, error) {
+	if !strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+		return "", fmt.Errorf("unsupported authentication challenge %q", challenge)
+	}
+	params := map[string]string{}
+	for _, m := range challengePattern.FindAllStringSubmatch(challenge, -1) {
+		params[strings.ToLower(m[1])] = m[2]
+	}
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return "", fmt.Errorf("invalid token realm in challenge %q", challenge)
+	}
+	query := realm.Query()
+	for _, key := range []string{"service", "scope"} {
+		if params[key] != "" {
+			query.Set(key, params[key])
+		}
+	}
+	realm.RawQuery = query.Encode()
+
+	resp, err := r.get(ctx, realm.String(), "")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token request failed: %s", resp.Status)
+	}
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to parse token response: %w", err)
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	return body.AccessToken, nil
+}
+
+// getAuthorized fetches target, retrying once with an anonymous bearer token when challenged
+func (r *SemverResolver) getAuthorized(ctx context.Context, target string, token *string) (*http.Response, error) {
+	resp, err := r.get(ctx, target, *token)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || *token != "" {
+		return resp, err
+	}
+	challenge := resp.Header.Get("WWW-Authenticate")
+	resp.Body.Close()
+	if *token, err = r.anonymousToken(ctx, challenge); err != nil {
+		return nil, err
+	}
+	return r.get(ctx, target, *token)
+}
+
+func (r *SemverResolver) get(ctx context.Context, target, token string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := r.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", target, err)
+	}
+	return resp, nil
+}
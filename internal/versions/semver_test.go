@@ -0,0 +1,196 @@
+package versions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testIndex = `apiVersion: v1
+entries:
+  gpu-operator:
+  - version: v25.0.0-rc1
+  - version: v24.9.2
+  - version: v24.9.0
+  - version: v24.3.0
+  rocm-device-plugin:
+  - version: 1.1.0
+  - version: 1.0.3
+`
+
+// newRegistry serves a Helm repository index and an OCI tag list requiring an anonymous token,
+// paginated over two pages
+func newRegistry(t *testing.T, indexFetches *int32) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/charts/index.yaml", func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(indexFetches, 1)
+		fmt.Fprint(w, testIndex)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "repository:nvidia/cuda:pull", r.URL.Query().Get("scope"))
+		_ = json.NewEncoder(w).Encode(map[string]string{"token": "anonymous"})
+	})
+	var server *httptest.Server
+	mux.HandleFunc("/v2/nvidia/cuda/tags/list", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer anonymous" {
+			w.Header().Set("WWW-Authenticate",
+				fmt.Sprintf(`Bearer realm="%s/token",service="registry",scope="repository:nvidia/cuda:pull"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		tags := []string{"12.4.1", "12.3.0", "latest"}
+		if r.URL.Query().Get("last") == "" {
+			w.Header().Set("Link", `</v2/nvidia/cuda/tags/list?n=3&last=latest>; rel="next"`)
+		} else {
+			tags = []string{"12.4.2-base", "12.5.0", "12.4.3"}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"name": "nvidia/cuda", "tags": tags})
+	})
+	server = httptest.NewTLSServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func testConstraints(server *httptest.Server, operator, runtime string) ChannelConstraints {
+	oci := "oci://" + strings.TrimPrefix(server.URL, "https://") + "/nvidia/cuda"
+	vendor := func(chart, operator string) VendorConstraints {
+		return VendorConstraints{
+			Operator: PinConstraint{Repo: server.URL + "/charts", Chart: chart, Constraint: operator},
+			Runtime:  PinConstraint{Repo: oci, Constraint: runtime},
+		}
+	}
+	return ChannelConstraints{
+		"stable": {
+			"nvidia": vendor("gpu-operator", operator),
+			"amd":    vendor("rocm-device-plugin", "~1.0"),
+			"intel":  vendor("gpu-operator", "~24.3"),
+		},
+	}
+}
+
+func TestSemverResolver_Resolve(t *testing.T) {
+	var fetches int32
+	server := newRegistry(t, &fetches)
+	resolver := NewSemverResolver(testConstraints(server, "~24.9", "~12.4"))
+	resolver.HTTPClient = server.Client()
+
+	pins, err := resolver.Resolve(context.Background(), "stable")
+	require.NoError(t, err)
+	assert.Equal(t, Pins{OperatorTag: "v24.9.2", RuntimeTag: "12.4.3"}, pins["nvidia"])
+	assert.Equal(t, Pins{OperatorTag: "1.0.3", RuntimeTag: "12.4.3"}, pins["amd"])
+	assert.Equal(t, "v24.3.0", pins["intel"].OperatorTag)
+
+	_, err = resolver.Resolve(context.Background(), "stable")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches), "the index is cached")
+}
+
+func TestSemverResolver_Resolve_PreRelease(t *testing.T) {
+	var fetches int32
+	server := newRegistry(t, &fetches)
+	resolver := NewSemverResolver(testConstraints(server, ">=25.0.0-0", ">=12.5"))
+	resolver.HTTPClient = server.Client()
+
+	pins, err := resolver.Resolve(context.Background(), "stable")
+	require.NoError(t, err)
+	assert.Equal(t, Pins{OperatorTag: "v25.0.0-rc1", RuntimeTag: "12.5.0"}, pins["nvidia"])
+}
+
+func TestSemverResolver_Resolve_ChartVersion(t *testing.T) {
+	var fetches int32
+	server := newRegistry(t, &fetches)
+	constraints := testConstraints(server, "~24.9", "~12.4")
+	nvidia := constraints["stable"]["nvidia"]
+	nvidia.Chart = &PinConstraint{Repo: server.URL + "/charts", Chart: "gpu-operator", Constraint: "~24.3"}
+	constraints["stable"]["nvidia"] = nvidia
+	resolver := NewSemverResolver(constraints)
+	resolver.HTTPClient = server.Client()
+
+	pins, err := resolver.Resolve(context.Background(), "stable")
+	require.NoError(t, err)
+	assert.Equal(t, Pins{OperatorTag: "v24.9.2", RuntimeTag: "12.4.3", ChartVersion: "v24.3.0"}, pins["nvidia"])
+	assert.Empty(t, pins["amd"].ChartVersion)
+}
+
+func TestSemverResolver_Resolve_Errors(t *testing.T) {
+	var fetches int32
+	server := newRegistry(t, &fetches)
+
+	resolver := NewSemverResolver(testConstraints(server, "~26.0", "~12.4"))
+	resolver.HTTPClient = server.Client()
+	_, err := resolver.Resolve(context.Background(), "stable")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "nvidia operator tag")
+	assert.Contains(t, err.Error(), "no version of")
+
+	resolver = NewSemverResolver(testConstraints(server, "not a constraint", "~12.4"))
+	resolver.HTTPClient = server.Client()
+	_, err = resolver.Resolve(context.Background(), "stable")
+	assert.ErrorContains(t, err, "invalid constraint")
+
+	_, err = resolver.Resolve(context.Background(), "lts")
+	assert.ErrorContains(t, err, "no version constraints for channel lts")
+
+	resolver = NewSemverResolver(ChannelConstraints{"stable": {}})
+	resolver.HTTPClient = server.Client()
+	_, err = resolver.Resolve(context.Background(), "stable")
+	assert.ErrorContains(t, err, "no vendor version pins")
+}
+
+func TestSemverResolver_OCITags_TooManyPages(t *testing.T) {
+	var pages int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := atomic.AddInt32(&pages, 1)
+		// Every page links to another one
+		w.Header().Set("Link", fmt.Sprintf(`</v2/nvidia/cuda/tags/list?n=1&last=12.%d.0>; rel="next"`, page))
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"name": "nvidia/cuda", "tags": []string{fmt.Sprintf("12.%d.0", page)}})
+	}))
+	t.Cleanup(server.Close)
+	resolver := NewSemverResolver(nil)
+	resolver.HTTPClient = server.Client()
+
+	repo := "oci://" + strings.TrimPrefix(server.URL, "https://") + "/nvidia/cuda"
+	_, err := resolver.ociTags(context.Background(), repo)
+	assert.EqualError(t, err, fmt.Sprintf("tag list of %s exceeds %d pages", repo, maxTagPages))
+	assert.Equal(t, int32(maxTagPages), atomic.LoadInt32(&pages))
+}
+
+func TestSemverResolver_Resolve_ConfiguredVendors(t *testing.T) {
+	var fetches int32
+	server := newRegistry(t, &fetches)
+	constraints := testConstraints(server, "~24.9", "~12.4")
+	delete(constraints["stable"], "intel")
+	resolver := NewSemverResolver(constraints)
+	resolver.HTTPClient = server.Client()
+
+	pins, err := resolver.Resolve(context.Background(), "stable")
+	require.NoError(t, err)
+	assert.Len(t, pins, 2)
+	_, ok := pins.ForVendor("intel")
+	assert.False(t, ok)
+}
+
+func TestLoadConstraints(t *testing.T) {
+	constraints, err := LoadConstraints([]byte(`
+stable:
+  nvidia:
+    operator:
+      repo: https://helm.ngc.nvidia.com/nvidia
+      chart: gpu-operator
+      constraint: "~24.9"
+    runtime:
+      repo: oci://nvcr.io/nvidia/cuda
+      constraint: "~12.4"
+`))
+	require.NoError(t, err)
+	assert.Equal(t, "gpu-operator", constraints["stable"]["nvidia"].Operator.Chart)
+	assert.Equal(t, "oci://nvcr.io/nvidia/cuda", constraints["stable"]["nvidia"].Runtime.Repo)
+}
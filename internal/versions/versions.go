@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -13,13 +15,27 @@ import (
 type Pins struct {
 	OperatorTag string `yaml:"operatorTag"`
 	RuntimeTag  string `yaml:"runtimeTag"`
+	// ChartVersion pins the version of the vendor chart, the latest chart is deployed when empty
+	ChartVersion string `yaml:"chartVersion,omitempty"`
 }
 
-// VendorPins represents version pins for all vendors
-type VendorPins struct {
-	NVIDIA Pins `yaml:"nvidia"`
-	AMD    Pins `yaml:"amd"`
-	Intel  Pins `yaml:"intel"`
+// VendorPins represents version pins keyed by lowercase vendor name
+type VendorPins map[string]Pins
+
+// HelmValues renders the pins as the Helm values injected into the vendor chart
+func (p Pins) HelmValues() map[string]interface{} {
+	return map[string]interface{}{
+		"image": map[string]interface{}{
+			"operatorTag": p.OperatorTag,
+			"runtimeTag":  p.RuntimeTag,
+		},
+	}
+}
+
+// ForVendor returns the pins for the given vendor name
+func (v VendorPins) ForVendor(vendor string) (Pins, bool) {
+	pins, ok := v[strings.ToLower(vendor)]
+	return pins, ok
 }
 
 // Resolver interface for resolving versions
@@ -45,17 +61,130 @@ func (r *FileResolver) Resolve(ctx context.Context, channel string) (VendorPins,
 
 	data, err := os.ReadFile(versionFile)
 	if err != nil {
-		return VendorPins{}, fmt.Errorf("failed to read version file %s: %w", versionFile, err)
+		return nil, fmt.Errorf("failed to read version file %s: %w", versionFile, err)
+	}
+
+	pins, err := ParseVersionFile(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version file %s: %w", versionFile, err)
+	}
+	return pins, nil
+}
+
+// versionFileKey is the ConfigMap data key holding a whole plain VERSION.yaml
+const versionFileKey = "VERSION.yaml"
+
+// ParseVersionFile parses VERSION.yaml content and validates it. The content is either the plain
+// vendor pins or a ConfigMap manifest carrying them under data, one key per vendor or a single
+// VERSION.yaml key. Per-vendor entries may be nested maps or YAML strings.
+func ParseVersionFile(data []byte) (VendorPins, error) {
+	var envelope struct {
+		Kind string               `yaml:"kind"`
+		Data map[string]yaml.Node `yaml:"data"`
+	}
+	if err := yaml.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal version file: %w", err)
 	}
 
 	var pins VendorPins
-	if err := yaml.Unmarshal(data, &pins); err != nil {
-		return VendorPins{}, fmt.Errorf("failed to unmarshal version file: %w", err)
+	if envelope.Kind == "ConfigMap" {
+		parsed, err := parseConfigMapData(envelope.Data)
+		if err != nil {
+			return nil, err
+		}
+		pins = parsed
+	} else if err := yaml.Unmarshal(data, &pins); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal version file: %w", err)
+	}
+
+	pins = pins.normalize()
+	if err := pins.Validate(); err != nil {
+		return nil, err
 	}
+	return pins, nil
+}
 
+// parseConfigMapData reads vendor pins from the data of a VERSION.yaml ConfigMap
+func parseConfigMapData(data map[string]yaml.Node) (VendorPins, error) {
+	if node, ok := data[versionFileKey]; ok {
+		var pins VendorPins
+		if err := decodeEntry(node, &pins); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal ConfigMap key %s: %w", versionFileKey, err)
+		}
+		return pins, nil
+	}
+
+	pins := make(VendorPins, len(data))
+	for vendor, node := range data {
+		var entry Pins
+		if err := decodeEntry(node, &entry); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal ConfigMap key %s: %w", vendor, err)
+		}
+		pins[vendor] = entry
+	}
 	return pins, nil
 }
 
+// ParseConfigMapData parses and validates the vendor pins held by the data of a version ConfigMap
+func ParseConfigMapData(data map[string]string) (VendorPins, error) {
+	nodes := make(map[string]yaml.Node, len(data))
+	for key, value := range data {
+		nodes[key] = yaml.Node{Kind: yaml.ScalarNode, Value: value}
+	}
+	pins, err := parseConfigMapData(nodes)
+	if err != nil {
+		return nil, err
+	}
+	pins = pins.normalize()
+	if err := pins.Validate(); err != nil {
+		return nil, err
+	}
+	return pins, nil
+}
+
+// decodeEntry decodes a ConfigMap data entry, either a nested map or a string holding YAML
+func decodeEntry(node yaml.Node, out interface{}) error {
+	if node.Kind == yaml.ScalarNode {
+		return yaml.Unmarshal([]byte(node.Value), out)
+	}
+	return node.Decode(out)
+}
+
+// normalize lowercases the vendor names
+func (v VendorPins) normalize() VendorPins {
+	normalized := make(VendorPins, len(v))
+	for vendor, pins := range v {
+		normalized[strings.ToLower(vendor)] = pins
+	}
+	return normalized
+}
+
+// Validate reports every vendor missing an operator or runtime tag
+func (v VendorPins) Validate() error {
+	if len(v) == 0 {
+		return fmt.Errorf("no vendor version pins")
+	}
+	vendors := make([]string, 0, len(v))
+	for vendor := range v {
+		vendors = append(vendors, vendor)
+	}
+	sort.Strings(vendors)
+
+	var missing []string
+	for _, vendor := range vendors {
+		if v[vendor].OperatorTag == "" {
+			missing = append(missing, vendor+".operatorTag")
+		}
+		if v[vendor].RuntimeTag == "" {
+			missing = append(missing, vendor+".runtimeTag")
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing version pins: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
 // LoadSources loads vendor sources from ConfigMap
 func LoadSources(ctx context.Context, configMapData map[string]string) (map[string]interface{}, error) {
 	sources := make(map[string]interface{})
@@ -44,12 +44,12 @@ intel:
 	require.NoError(t, err)
 
 	// Verify results
-	assert.Equal(t, "v24.9.0", pins.NVIDIA.OperatorTag)
-	assert.Equal(t, "12.4.1", pins.NVIDIA.RuntimeTag)
-	assert.Equal(t, "v24.9.0", pins.AMD.OperatorTag)
-	assert.Equal(t, "5.7.1", pins.AMD.RuntimeTag)
-	assert.Equal(t, "v24.9.0", pins.Intel.OperatorTag)
-	assert.Equal(t, "24.16.0", pins.Intel.RuntimeTag)
+	assert.Equal(t, "v24.9.0", pins["nvidia"].OperatorTag)
+	assert.Equal(t, "12.4.1", pins["nvidia"].RuntimeTag)
+	assert.Equal(t, "v24.9.0", pins["amd"].OperatorTag)
+	assert.Equal(t, "5.7.1", pins["amd"].RuntimeTag)
+	assert.Equal(t, "v24.9.0", pins["intel"].OperatorTag)
+	assert.Equal(t, "24.16.0", pins["intel"].RuntimeTag)
 }
 
 func TestFileResolver_Resolve_NonExistentChannel(t *testing.T) {
@@ -85,6 +85,94 @@ func TestFileResolver_Resolve_InvalidYAML(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestFileResolver_Resolve_ShippedOverlays(t *testing.T) {
+	resolver := NewFileResolver(filepath.Join("..", "..", "fleet", "overlays"))
+
+	for _, channel := range []string{"stable", "lts", "canary"} {
+		pins, err := resolver.Resolve(context.Background(), channel)
+		require.NoError(t, err, channel)
+		assert.NoError(t, pins.Validate(), channel)
+	}
+
+	stable, err := resolver.Resolve(context.Background(), "stable")
+	require.NoError(t, err)
+	assert.Equal(t, Pins{OperatorTag: "v24.9.0", RuntimeTag: "v1.17.0", ChartVersion: "v24.9.0"}, stable["nvidia"])
+	assert.Equal(t, Pins{OperatorTag: "v0.4.0", RuntimeTag: "23.3.0"}, stable["intel"])
+}
+
+func TestParseVersionFile_ConfigMapStringData(t *testing.T) {
+	pins, err := ParseVersionFile([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: version-config
+data:
+  nvidia: |
+    operatorTag: "v24.9.0"
+    runtimeTag: "12.4.1"
+  amd: |
+    operatorTag: "v1.0.0"
+    runtimeTag: "5.7.1"
+  intel: |
+    operatorTag: "v0.4.0"
+    runtimeTag: "23.3.0"
+`))
+	require.NoError(t, err)
+	assert.Equal(t, "12.4.1", pins["nvidia"].RuntimeTag)
+	assert.Equal(t, "v1.0.0", pins["amd"].OperatorTag)
+
+	pins, err = ParseVersionFile([]byte(`
+kind: ConfigMap
+data:
+  VERSION.yaml: |
+    nvidia: {operatorTag: "v24.9.0", runtimeTag: "12.4.1"}
+    amd: {operatorTag: "v1.0.0", runtimeTag: "5.7.1"}
+    intel: {operatorTag: "v0.4.0", runtimeTag: "23.3.0"}
+`))
+	require.NoError(t, err)
+	assert.Equal(t, "23.3.0", pins["intel"].RuntimeTag)
+}
+
+func TestParseVersionFile_MissingTags(t *testing.T) {
+	_, err := ParseVersionFile([]byte(`
+kind: ConfigMap
+data:
+  nvidia:
+    operatorTag: "v24.9.0"
+  amd:
+    operatorTag: "v1.0.0"
+    runtimeTag: "5.7.1"
+`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "nvidia.runtimeTag")
+	assert.NotContains(t, err.Error(), "amd")
+
+	_, err = ParseVersionFile([]byte(`kind: ConfigMap`))
+	assert.ErrorContains(t, err, "no vendor version pins")
+}
+
+func TestParseVersionFile_AdditionalVendors(t *testing.T) {
+	pins, err := ParseVersionFile([]byte(`
+kind: ConfigMap
+data:
+  Habana: |
+    operatorTag: "1.19.0"
+    runtimeTag: "1.19.0-561"
+  neuron: |
+    operatorTag: "2.21.0"
+    runtimeTag: "2.21.0"
+`))
+	require.NoError(t, err)
+	assert.Equal(t, VendorPins{
+		"habana": {OperatorTag: "1.19.0", RuntimeTag: "1.19.0-561"},
+		"neuron": {OperatorTag: "2.21.0", RuntimeTag: "2.21.0"},
+	}, pins)
+
+	habana, ok := pins.ForVendor("HABANA")
+	assert.True(t, ok)
+	assert.Equal(t, "1.19.0", habana.OperatorTag)
+}
+
 func TestLoadSources(t *testing.T) {
 	ctx := context.Background()
 
@@ -139,3 +227,46 @@ func TestLoadSources_EmptyData(t *testing.T) {
 	assert.NotNil(t, sources)
 	assert.Empty(t, sources)
 }
+
+func TestVendorPins_ForVendor(t *testing.T) {
+	pins := VendorPins{
+		"nvidia": {OperatorTag: "v24.9.0", RuntimeTag: "12.4.1"},
+		"amd":    {OperatorTag: "v1.0.0", RuntimeTag: "5.7.1"},
+		"intel":  {OperatorTag: "v0.4.0", RuntimeTag: "23.3.0"},
+	}
+
+	nvidia, ok := pins.ForVendor("NVIDIA")
+	assert.True(t, ok)
+	assert.Equal(t, "12.4.1", nvidia.RuntimeTag)
+
+	amd, ok := pins.ForVendor("amd")
+	assert.True(t, ok)
+	assert.Equal(t, "v1.0.0", amd.OperatorTag)
+
+	_, ok = pins.ForVendor("habana")
+	assert.False(t, ok)
+}
+
+func TestPins_HelmValues(t *testing.T) {
+	values := Pins{OperatorTag: "v24.9.0", RuntimeTag: "12.4.1"}.HelmValues()
+
+	image := values["image"].(map[string]interface{})
+	assert.Equal(t, "v24.9.0", image["operatorTag"])
+	assert.Equal(t, "12.4.1", image["runtimeTag"])
+}
+
+func TestParseVersionFile_ChartVersion(t *testing.T) {
+	pins, err := ParseVersionFile([]byte(`
+nvidia:
+  operatorTag: "v24.9.0"
+  runtimeTag: "12.4.1"
+  chartVersion: "v24.9.0"
+amd:
+  operatorTag: "v1.0.0"
+  runtimeTag: "5.7.1"
+`))
+	require.NoError(t, err)
+	assert.Equal(t, "v24.9.0", pins["nvidia"].ChartVersion)
+	assert.Empty(t, pins["amd"].ChartVersion)
+	assert.NotContains(t, pins["nvidia"].HelmValues()["image"], "chartVersion")
+}
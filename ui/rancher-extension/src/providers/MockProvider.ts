@@ -6,6 +6,9 @@ export interface Channel {
   phase: 'Pending' | 'Progressing' | 'Succeeded' | 'Failed';
   observedVersion: string;
   clusterCount: number;
+  readyClusters: number;
+  failedClusters: number;
+  pendingClusters: number;
   lastUpdated: string;
 }
 
@@ -57,6 +60,9 @@ export class MockProvider {
       phase: 'Succeeded',
       observedVersion: 'v24.9.0/12.4.1',
       clusterCount: 3,
+      readyClusters: 3,
+      failedClusters: 0,
+      pendingClusters: 0,
       lastUpdated: '2024-01-15T10:30:00Z'
     },
     {
@@ -67,6 +73,9 @@ export class MockProvider {
       phase: 'Progressing',
       observedVersion: 'v0.9.0/5.6.0',
       clusterCount: 2,
+      readyClusters: 1,
+      failedClusters: 0,
+      pendingClusters: 1,
       lastUpdated: '2024-01-15T09:15:00Z'
     },
     {
@@ -77,6 +86,9 @@ export class MockProvider {
       phase: 'Failed',
       observedVersion: 'v0.5.0-rc1/23.4.0-rc1',
       clusterCount: 1,
+      readyClusters: 0,
+      failedClusters: 1,
+      pendingClusters: 0,
       lastUpdated: '2024-01-15T08:45:00Z'
     }
   ];